@@ -68,6 +68,7 @@ func init() {
 	})
 
 	RootCmd.AddCommand(cmd.NewValidateCmd())
+	RootCmd.AddCommand(cmd.NewRulesCmd())
 
 	RootCmd.PersistentPreRun = func(command *cobra.Command, args []string) {
 		if Verbose {
@@ -116,13 +117,24 @@ var versionCmd = &cobra.Command{
 var docsCmd = &cobra.Command{
 	Use:    "docs",
 	Hidden: true,
-	Run: func(cmd *cobra.Command, args []string) {
+	Run: func(_ *cobra.Command, _ []string) {
 		RootCmd.DisableAutoGenTag = true
 
 		err := doc.GenMarkdownTree(RootCmd, "./docs")
 		if err != nil {
 			panic(err)
 		}
+
+		f, err := os.Create("./docs/rules.md")
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+
+		err = cmd.GenRulesMarkdown(f)
+		if err != nil {
+			panic(err)
+		}
 	},
 }
 
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+const (
+	// Stdin is the path used to read from standard input.
+	Stdin = "-"
+
+	stdinName = "<stdin>"
+)
+
+// ExpandPaths resolves each path into the files it refers to. Directories are
+// walked recursively and glob patterns, including '**', are expanded, only
+// files matching one of the include patterns are returned from either. Any
+// file matching an exclude pattern is always skipped.
+func ExpandPaths(paths []string, include []string, exclude []string) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+
+	add := func(file string) {
+		if !seen[file] && !matchesAny(exclude, file) {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, path := range paths {
+		if path == Stdin {
+			add(Stdin)
+			continue
+		}
+
+		matches := []string{path}
+		if hasMeta(path) {
+			var err error
+			matches, err = doublestar.FilepathGlob(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s: %w", path, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %s", path)
+			}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+
+			if !info.IsDir() {
+				if match == path || matchesAny(include, match) {
+					add(match)
+				}
+				continue
+			}
+
+			err = filepath.WalkDir(match, func(file string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && matchesAny(include, file) {
+					add(file)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// matchesAny reports whether the file, or its base name, matches any of the patterns.
+func matchesAny(patterns []string, file string) bool {
+	slashed := filepath.ToSlash(file)
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(pattern, slashed); ok {
+			return true
+		}
+		if ok, _ := doublestar.Match(pattern, filepath.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMeta(path string) bool {
+	for _, c := range path {
+		switch c {
+		case '*', '?', '[', '{':
+			return true
+		}
+	}
+	return false
+}
+
+// readFile reads the contents of file, returning the name it should be reported as.
+func readFile(file string, stdin io.Reader) (string, []byte, error) {
+	if file == Stdin {
+		b, err := io.ReadAll(stdin)
+		return stdinName, b, err
+	}
+
+	b, err := os.ReadFile(file)
+	return file, b, err
+}
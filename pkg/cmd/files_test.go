@@ -0,0 +1,91 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/cmd"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{
+		"config/carvel.yaml",
+		"config/tasks/task.yml",
+		"config/tasks/README.md",
+		"config/test/fixture.yaml",
+		"other.json",
+	} {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("---"), 0o600))
+	}
+
+	include := []string{"*.yaml", "*.yml", "*.json"}
+
+	tests := []struct {
+		name     string
+		paths    []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			name:     "file",
+			paths:    []string{"config/tasks/README.md"},
+			expected: []string{"config/tasks/README.md"},
+		},
+		{
+			name:     "directory",
+			paths:    []string{"config"},
+			expected: []string{"config/carvel.yaml", "config/tasks/task.yml", "config/test/fixture.yaml"},
+		},
+		{
+			name:     "directory with exclude",
+			paths:    []string{"config"},
+			exclude:  []string{"**/test/**"},
+			expected: []string{"config/carvel.yaml", "config/tasks/task.yml"},
+		},
+		{
+			name:     "glob",
+			paths:    []string{"config/**/*.yaml"},
+			expected: []string{"config/carvel.yaml", "config/test/fixture.yaml"},
+		},
+		{
+			name:     "multiple paths without duplicates",
+			paths:    []string{"config/carvel.yaml", "*.json", "-", "config/*.yaml"},
+			expected: []string{"config/carvel.yaml", "other.json", "-"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var paths []string
+			for _, path := range tc.paths {
+				if path != cmd.Stdin {
+					path = filepath.Join(dir, path)
+				}
+				paths = append(paths, path)
+			}
+
+			files, err := cmd.ExpandPaths(paths, include, tc.exclude)
+			require.NoError(t, err)
+
+			var expected []string
+			for _, file := range tc.expected {
+				if file != cmd.Stdin {
+					file = filepath.Join(dir, file)
+				}
+				expected = append(expected, file)
+			}
+
+			assert.Equal(t, expected, files)
+		})
+	}
+
+	_, err := cmd.ExpandPaths([]string{filepath.Join(dir, "missing/*.yaml")}, include, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+)
+
+// fix resolves the fixable diagnostics of each source, writing the fixed
+// source back to its file. When dryRun is set the files are left unchanged and
+// a unified diff of the fixes is written to out instead. Either way the sources
+// are updated with the fixes, so they can be validated afterwards.
+func fix(ctx context.Context, v *validator.Validator, out io.Writer, sources []validator.Source, dryRun bool) error {
+	for i, s := range sources {
+		fixed, report, err := v.Fix(ctx, s.File, s.Data)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range report.Unfixed {
+			logrus.Warnf("unable to fix %s: %s", u.String(), u.Reason)
+		}
+
+		if len(report.Fixed) == 0 {
+			continue
+		}
+
+		for _, d := range report.Fixed {
+			logrus.Debugf("fixing %s", d.String())
+		}
+
+		if dryRun {
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(string(s.Data)),
+				B:        difflib.SplitLines(string(fixed)),
+				FromFile: s.File,
+				ToFile:   s.File,
+				Context:  3,
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.WriteString(out, diff); err != nil {
+				return err
+			}
+			sources[i].Data = fixed
+			continue
+		}
+
+		if s.File == stdinName {
+			return fmt.Errorf("unable to fix %s in place, use --dry-run to print the fixes", stdinName)
+		}
+
+		info, err := os.Stat(s.File)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(s.File, fixed, info.Mode().Perm()); err != nil {
+			return err
+		}
+
+		logrus.Infof("fixed %d violations in %s", len(report.Fixed), s.File)
+		sources[i].Data = fixed
+	}
+
+	return nil
+}
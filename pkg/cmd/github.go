@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+)
+
+const githubActions = "GITHUB_ACTIONS"
+
+// runningInGitHubActions reports whether the command is running within a
+// GitHub Actions workflow, see
+// https://docs.github.com/en/actions/learn-github-actions/variables#default-environment-variables
+func runningInGitHubActions() bool {
+	return os.Getenv(githubActions) == "true"
+}
+
+// writeGitHub writes each diagnostic as a workflow command so it is
+// displayed as an annotation against the file, see
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+func writeGitHub(w io.Writer, report *validator.Report) error {
+	for _, d := range report.Diagnostics {
+		var properties []string
+		if d.Position.File != "" && d.Position.File != stdinName {
+			properties = append(properties, "file="+escapeProperty(filepath.ToSlash(d.Position.File)))
+		}
+		if d.Position.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", d.Position.Line))
+		}
+		if d.Position.Column > 0 {
+			properties = append(properties, fmt.Sprintf("col=%d", d.Position.Column))
+		}
+		properties = append(properties, "title="+escapeProperty(d.Rule))
+
+		_, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(d.Severity), strings.Join(properties, ","), escapeData(d.Error()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func githubCommand(s validator.Severity) string {
+	switch s {
+	case validator.SeverityError:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+)
+
+// The JUnit XML format as understood by Jenkins, Concourse and most CI
+// systems, each file is a testsuite and each resource within it a testcase.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Skipped   *junitSkipped  `xml:"skipped,omitempty"`
+	Failures  []junitFailure `xml:"failure,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+type junitKey struct {
+	file     string
+	document int
+}
+
+func writeJUnit(w io.Writer, report *validator.Report) error {
+	var (
+		files []string
+		keys  = map[string][]junitKey{}
+		cases = map[junitKey]*junitTestCase{}
+	)
+
+	testCase := func(p validator.Position, name string) *junitTestCase {
+		key := junitKey{file: p.File, document: p.Document}
+		if _, ok := cases[key]; !ok {
+			if _, ok := keys[p.File]; !ok {
+				files = append(files, p.File)
+			}
+			keys[p.File] = append(keys[p.File], key)
+			cases[key] = &junitTestCase{Name: name, ClassName: suiteName(p.File)}
+		}
+		return cases[key]
+	}
+
+	for _, r := range report.Resources {
+		testCase(r.Position, fmt.Sprintf("%s/%s", r.Kind, r.Name))
+	}
+
+	for _, r := range report.Skipped {
+		tc := testCase(r.Position, fmt.Sprintf("%s/%s", r.Kind, r.Name))
+		tc.Skipped = &junitSkipped{Message: fmt.Sprintf("no validation specified for %s", r.Kind)}
+	}
+
+	for _, d := range report.Diagnostics {
+		name := fmt.Sprintf("%s/%s", d.Kind, d.Name)
+		if d.Kind == "" {
+			name = fmt.Sprintf("document %d", d.Position.Document)
+		}
+
+		tc := testCase(d.Position, name)
+		tc.Failures = append(tc.Failures, junitFailure{
+			Message:  fmt.Sprintf("[%s] %s", d.Rule, d.Message),
+			Type:     d.Rule,
+			Contents: fmt.Sprintf("%s: %s %s", d.Position, strings.ToUpper(string(d.Severity)), d.Message),
+		})
+	}
+
+	out := junitTestSuites{Name: toolName}
+	for _, file := range files {
+		sort.Slice(keys[file], func(i, j int) bool {
+			return keys[file][i].document < keys[file][j].document
+		})
+
+		suite := junitTestSuite{Name: suiteName(file)}
+		for _, key := range keys[file] {
+			tc := cases[key]
+
+			suite.Tests++
+			if tc.Skipped != nil {
+				suite.Skipped++
+			}
+			if len(tc.Failures) > 0 {
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, *tc)
+		}
+
+		out.Tests += suite.Tests
+		out.Skipped += suite.Skipped
+		out.Failures += suite.Failures
+		out.Suites = append(out.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func suiteName(file string) string {
+	if file == "" {
+		return toolName
+	}
+	return file
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OutputText   = "text"
+	OutputJSON   = "json"
+	OutputSARIF  = "sarif"
+	OutputJUnit  = "junit"
+	OutputGitHub = "github"
+)
+
+type writer func(w io.Writer, report *validator.Report) error
+
+type writers map[string]writer
+
+var outputs = writers{
+	OutputText:   writeText,
+	OutputJSON:   writeJSON,
+	OutputSARIF:  writeSARIF,
+	OutputJUnit:  writeJUnit,
+	OutputGitHub: writeGitHub,
+}
+
+// String lists the supported formats for use in help and error messages.
+func (w writers) String() string {
+	var names []string
+	for name := range w {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return fmt.Sprint(names)
+}
+
+// writeText logs each diagnostic at the level matching its severity.
+func writeText(_ io.Writer, report *validator.Report) error {
+	for _, r := range report.Skipped {
+		logrus.Infof("%s: no validation specified for %s", r.Position, r.Kind)
+	}
+
+	for _, d := range report.Diagnostics {
+		switch d.Severity {
+		case validator.SeverityError:
+			logrus.Errorf("%s", d.String())
+		case validator.SeverityWarning:
+			logrus.Warnf("%s", d.String())
+		default:
+			logrus.Infof("%s", d.String())
+		}
+	}
+
+	for _, s := range report.Suppressed {
+		logrus.Infof("%s (suppressed: %s)", s.String(), s.Reason)
+	}
+
+	if len(report.Baselined) > 0 {
+		logrus.Infof("%d violations matched the baseline", len(report.Baselined))
+	}
+	return nil
+}
+
+// Summary counts the resources and diagnostics reported.
+type Summary struct {
+	Resources  int `json:"resources"`
+	Skipped    int `json:"skipped"`
+	Errors     int `json:"errors"`
+	Warnings   int `json:"warnings"`
+	Info       int `json:"info"`
+	Suppressed int `json:"suppressed"`
+	Baselined  int `json:"baselined"`
+}
+
+func (s *Summary) add(d validator.Diagnostic) {
+	switch d.Severity {
+	case validator.SeverityError:
+		s.Errors++
+	case validator.SeverityWarning:
+		s.Warnings++
+	default:
+		s.Info++
+	}
+}
+
+type jsonReport struct {
+	Violations []validator.Diagnostic  `json:"violations"`
+	Summary    Summary                 `json:"summary"`
+	Kinds      map[string]*Summary     `json:"kinds"`
+	Skipped    []validator.Resource    `json:"skipped"`
+	Suppressed []validator.Suppression `json:"suppressed"`
+}
+
+func writeJSON(w io.Writer, report *validator.Report) error {
+	out := jsonReport{
+		Violations: report.Diagnostics,
+		Kinds:      map[string]*Summary{},
+		Skipped:    report.Skipped,
+		Suppressed: report.Suppressed,
+	}
+
+	if out.Violations == nil {
+		out.Violations = []validator.Diagnostic{}
+	}
+	if out.Skipped == nil {
+		out.Skipped = []validator.Resource{}
+	}
+	if out.Suppressed == nil {
+		out.Suppressed = []validator.Suppression{}
+	}
+
+	kind := func(name string) *Summary {
+		if _, ok := out.Kinds[name]; !ok {
+			out.Kinds[name] = &Summary{}
+		}
+		return out.Kinds[name]
+	}
+
+	for _, r := range report.Resources {
+		out.Summary.Resources++
+		kind(r.Kind).Resources++
+	}
+
+	for _, r := range report.Skipped {
+		out.Summary.Skipped++
+		kind(r.Kind).Skipped++
+	}
+
+	for _, d := range report.Diagnostics {
+		out.Summary.add(d)
+		if d.Kind != "" {
+			kind(d.Kind).add(d)
+		}
+	}
+
+	for _, s := range report.Suppressed {
+		out.Summary.Suppressed++
+		kind(s.Kind).Suppressed++
+	}
+
+	for _, d := range report.Baselined {
+		out.Summary.Baselined++
+		if d.Kind != "" {
+			kind(d.Kind).Baselined++
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
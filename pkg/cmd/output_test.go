@@ -0,0 +1,259 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/garethjevans/component-validator/pkg/cmd"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const outputDoc = `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: My_Pipeline
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+`
+
+func runValidate(t *testing.T, doc string, args ...string) (string, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "carvel.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+
+	var out bytes.Buffer
+	c := cmd.NewValidateCmd()
+	c.SetArgs(append([]string{"--path", path}, args...))
+	c.SetOut(&out)
+	c.SetErr(&bytes.Buffer{})
+
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestOutputJSON(t *testing.T) {
+	out, err := runValidate(t, outputDoc, "--output", "json")
+	assert.Error(t, err)
+
+	var report struct {
+		Violations []validator.Diagnostic `json:"violations"`
+		Summary    cmd.Summary            `json:"summary"`
+		Kinds      map[string]cmd.Summary `json:"kinds"`
+		Skipped    []validator.Resource   `json:"skipped"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+
+	require.Len(t, report.Violations, 1)
+	assert.Equal(t, "CV-PIPELINE-002", report.Violations[0].Rule)
+	assert.Equal(t, "metadata.name", report.Violations[0].Field)
+	assert.Equal(t, 5, report.Violations[0].Position.Line)
+
+	assert.Equal(t, cmd.Summary{Resources: 1, Skipped: 1, Errors: 1}, report.Summary)
+	assert.Equal(t, cmd.Summary{Resources: 1, Errors: 1}, report.Kinds["Pipeline"])
+	assert.Equal(t, cmd.Summary{Skipped: 1}, report.Kinds["ConfigMap"])
+
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, "my-config", report.Skipped[0].Name)
+	assert.Equal(t, 7, report.Skipped[0].Position.Line)
+}
+
+func TestOutputInvalid(t *testing.T) {
+	_, err := runValidate(t, outputDoc, "--output", "xml")
+	assert.ErrorContains(t, err, `invalid --output "xml"`)
+}
+
+func TestOutputSARIF(t *testing.T) {
+	out, err := runValidate(t, outputDoc, "--output", "sarif")
+	assert.Error(t, err)
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID   string `json:"id"`
+						Help struct {
+							Text string `json:"text"`
+						} `json:"help"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	driver := log.Runs[0].Tool.Driver
+	assert.Equal(t, "component-validator", driver.Name)
+	assert.Len(t, driver.Rules, len(validator.Rules))
+	for _, r := range driver.Rules {
+		assert.NotEmpty(t, r.Help.Text, r.ID)
+	}
+
+	require.Len(t, log.Runs[0].Results, 1)
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "CV-PIPELINE-002", result.RuleID)
+	assert.Equal(t, "CV-PIPELINE-002", driver.Rules[result.RuleIndex].ID)
+	assert.Equal(t, "error", result.Level)
+
+	require.Len(t, result.Locations, 1)
+	location := result.Locations[0].PhysicalLocation
+	assert.Contains(t, location.ArtifactLocation.URI, "file://")
+	assert.Equal(t, 5, location.Region.StartLine)
+	assert.Equal(t, 3, location.Region.StartColumn)
+}
+
+func TestOutputJUnit(t *testing.T) {
+	out, err := runValidate(t, outputDoc, "--output", "junit")
+	assert.Error(t, err)
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			TestCases []struct {
+				Name    string `xml:"name,attr"`
+				Skipped *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"skipped"`
+				Failures []struct {
+					Message string `xml:"message,attr"`
+					Type    string `xml:"type,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(out), &suites))
+
+	assert.Equal(t, 2, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+
+	require.Len(t, suites.Suites, 1)
+	cases := suites.Suites[0].TestCases
+	require.Len(t, cases, 2)
+
+	assert.Equal(t, "Pipeline/My_Pipeline", cases[0].Name)
+	require.Len(t, cases[0].Failures, 1)
+	assert.Equal(t, "CV-PIPELINE-002", cases[0].Failures[0].Type)
+	assert.Equal(t, "[CV-PIPELINE-002] Key 'Metadata.Name': My_Pipeline does not appear to be in kebab-case", cases[0].Failures[0].Message)
+
+	assert.Equal(t, "ConfigMap/my-config", cases[1].Name)
+	require.NotNil(t, cases[1].Skipped)
+	assert.Equal(t, "no validation specified for ConfigMap", cases[1].Skipped.Message)
+}
+
+func TestOutputGitHub(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	doc := outputDoc + `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: []
+      seccompProfile:
+        type: RuntimeDefault
+`
+
+	out, err := runValidate(t, doc)
+	assert.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^::error file=.*/carvel.yaml,line=5,col=3,title=CV-PIPELINE-002::Pipeline/My_Pipeline Key 'Metadata.Name': My_Pipeline does not appear to be in kebab-case$`, lines[0])
+	assert.Regexp(t, `^::warning file=.*/carvel.yaml,line=18,col=5,title=CV-TASK-004::Task/my-task Key 'Spec.StepTemplate.SecurityContext.RunAsUser': Runs as root, please ensure this was intended$`, lines[1])
+
+	out, err = runValidate(t, doc, "--output", "json")
+	assert.Error(t, err)
+	assert.True(t, json.Valid([]byte(out)))
+}
+
+func TestOutputSuppressed(t *testing.T) {
+	doc := `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: My_Pipeline
+  annotations:
+    component-validator.dev/ignore: CV-PIPELINE-002
+    component-validator.dev/ignore-reason: named by another team
+`
+
+	out, err := runValidate(t, doc, "--output", "json")
+	require.NoError(t, err)
+
+	var report struct {
+		Violations []validator.Diagnostic  `json:"violations"`
+		Summary    cmd.Summary             `json:"summary"`
+		Suppressed []validator.Suppression `json:"suppressed"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+
+	assert.Empty(t, report.Violations)
+	assert.Equal(t, cmd.Summary{Resources: 1, Suppressed: 1}, report.Summary)
+	require.Len(t, report.Suppressed, 1)
+	assert.Equal(t, "CV-PIPELINE-002", report.Suppressed[0].Rule)
+	assert.Equal(t, "named by another team", report.Suppressed[0].Reason)
+
+	out, err = runValidate(t, doc, "--output", "sarif")
+	require.NoError(t, err)
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID       string `json:"ruleId"`
+				Suppressions []struct {
+					Kind          string `json:"kind"`
+					Justification string `json:"justification"`
+				} `json:"suppressions"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &log))
+
+	require.Len(t, log.Runs[0].Results, 1)
+	require.Len(t, log.Runs[0].Results[0].Suppressions, 1)
+	assert.Equal(t, "inSource", log.Runs[0].Results[0].Suppressions[0].Kind)
+	assert.Equal(t, "named by another team", log.Runs[0].Results[0].Suppressions[0].Justification)
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRulesCmd creates the command describing the rules checked by validate.
+func NewRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Lists and explains the rules checked by validate",
+		Example: `component-validator rules list
+component-validator rules explain CV-TASK-007`,
+		Args: cobra.NoArgs,
+	}
+
+	list := &cobra.Command{
+		Use:          "list",
+		Short:        "Lists every rule and whether it is enabled by the config",
+		Args:         cobra.NoArgs,
+		RunE:         listRules,
+		SilenceUsage: true,
+	}
+
+	explain := &cobra.Command{
+		Use:          "explain <ID>",
+		Short:        "Explains why a rule is checked and how to fix it",
+		Args:         cobra.ExactArgs(1),
+		RunE:         explainRule,
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&ConfigFile, "config", "", fmt.Sprintf("The file configuring the rules to check, defaults to %s if present", validator.DefaultConfigFile))
+	cmd.AddCommand(list, explain)
+	return cmd
+}
+
+func listRules(cmd *cobra.Command, _ []string) error {
+	config, err := loadConfig(nil)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKIND\tSEVERITY\tENABLED\tDESCRIPTION")
+
+	for _, r := range validator.Rules {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", r.ID, kindOf(r.Kind), config.Severity(r.ID, r.Severity), config.Enabled(r.ID), r.Description)
+	}
+
+	if config != nil {
+		for _, r := range config.CustomRules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", r.ID, kindOf(strings.Join(r.Match.Kinds, ",")), config.Severity(r.ID, customSeverity(r)), config.Enabled(r.ID), r.Description)
+		}
+	}
+
+	return w.Flush()
+}
+
+func explainRule(cmd *cobra.Command, args []string) error {
+	config, err := loadConfig(nil)
+	if err != nil {
+		return err
+	}
+
+	id := args[0]
+	out := cmd.OutOrStdout()
+
+	if r, ok := validator.LookupRule(id); ok {
+		explain(out, r, config)
+		return nil
+	}
+
+	if r, ok := config.CustomRule(id); ok {
+		fmt.Fprintf(out, "%s: %s\n\n", r.ID, r.Description)
+		fmt.Fprintf(out, "Kind:       %s\n", kindOf(strings.Join(r.Match.Kinds, ",")))
+		fmt.Fprintf(out, "Severity:   %s\n", config.Severity(r.ID, customSeverity(r)))
+		fmt.Fprintf(out, "Enabled:    %t\n", config.Enabled(r.ID))
+		fmt.Fprintf(out, "Expression: %s\n", r.Expression)
+		return nil
+	}
+
+	return fmt.Errorf("unknown rule %q, run rules list to see every rule", id)
+}
+
+// explain writes the details of a built in rule, applying config.
+func explain(out io.Writer, r validator.Rule, config *validator.Config) {
+	fmt.Fprintf(out, "%s: %s\n\n", r.ID, r.Description)
+	fmt.Fprintf(out, "Kind:     %s\n", kindOf(r.Kind))
+	fmt.Fprintf(out, "Severity: %s\n", config.Severity(r.ID, r.Severity))
+	fmt.Fprintf(out, "Enabled:  %t\n", config.Enabled(r.ID))
+	fmt.Fprintf(out, "Fixable:  %t\n", r.Fixable())
+
+	if r.Rationale != "" {
+		fmt.Fprintf(out, "\nWhy:\n  %s\n", r.Rationale)
+	}
+	fmt.Fprintf(out, "\nHow to fix:\n  %s\n", r.Help)
+
+	if len(r.Params) > 0 {
+		fmt.Fprintf(out, "\nParameters:\n")
+		for _, p := range r.Params {
+			fmt.Fprintf(out, "  %s: %s (current %s)\n", p.Name, p.Description, strings.Join(config.Param(r.ID, p.Name), ", "))
+		}
+	}
+
+	if e, ok := r.Example(); ok {
+		fmt.Fprintf(out, "\nFailing example:\n%s", indent(e.Failing))
+		fmt.Fprintf(out, "\nPassing example:\n%s", indent(e.Passing))
+	}
+}
+
+// GenRulesMarkdown writes a reference page describing every built in rule.
+func GenRulesMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Rules\n\n")
+	b.WriteString("Every rule checked by `component-validator validate`, rules are configured in ")
+	b.WriteString("`" + validator.DefaultConfigFile + "`.\n\n")
+	b.WriteString("| ID | Kind | Severity | Fixable | Description |\n")
+	b.WriteString("|----|------|----------|---------|-------------|\n")
+	for _, r := range validator.Rules {
+		fmt.Fprintf(&b, "| [%s](#%s) | %s | %s | %s | %s |\n", r.ID, strings.ToLower(r.ID), kindOf(r.Kind), r.Severity, yesNo(r.Fixable()), r.Description)
+	}
+
+	for _, r := range validator.Rules {
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n\n", r.ID, r.Description)
+		if r.Rationale != "" {
+			fmt.Fprintf(&b, "%s\n\n", r.Rationale)
+		}
+		if r.Optional {
+			fmt.Fprintf(&b, "This rule is optional, it is only checked when enabled in `%s`.\n\n", validator.DefaultConfigFile)
+		}
+		fmt.Fprintf(&b, "**How to fix:** %s\n", r.Help)
+
+		if len(r.Params) > 0 {
+			b.WriteString("\n| Parameter | Description | Default |\n")
+			b.WriteString("|-----------|-------------|---------|\n")
+			for _, p := range r.Params {
+				fmt.Fprintf(&b, "| `%s` | %s | `%s` |\n", p.Name, p.Description, strings.Join(p.Default, ", "))
+			}
+		}
+
+		if e, ok := r.Example(); ok {
+			fmt.Fprintf(&b, "\nFailing example:\n\n```yaml\n%s```\n", e.Failing)
+			fmt.Fprintf(&b, "\nPassing example:\n\n```yaml\n%s```\n", e.Passing)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func customSeverity(r validator.CustomRule) validator.Severity {
+	if r.Severity == "" {
+		return validator.SeverityError
+	}
+	return r.Severity
+}
+
+// kindOf describes the kinds a rule applies to, rules without a kind apply to
+// every resource.
+func kindOf(kind string) string {
+	if kind == "" {
+		return "*"
+	}
+	return kind
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func indent(s string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" {
+			b.WriteString("  " + line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/cmd"
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runRules(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	c := cmd.NewRulesCmd()
+	c.SetArgs(args)
+	c.SetOut(&out)
+	c.SetErr(&bytes.Buffer{})
+
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestRulesList(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(`rules:
+  CV-TASK-004:
+    enabled: false
+  CV-PIPELINE-002:
+    severity: warning
+customRules:
+- id: ORG-TASK-001
+  description: Tasks must have a team label
+  match:
+    kinds: [Task]
+  expression: "has(object.metadata.labels.team)"
+`), 0o600))
+
+	out, err := runRules(t, "list", "--config", config)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, len(validator.Rules)+2)
+	assert.Regexp(t, `^ID\s+KIND\s+SEVERITY\s+ENABLED\s+DESCRIPTION$`, lines[0])
+	assert.Regexp(t, `(?m)^CV-TASK-004\s+Task\s+warning\s+false\s+Steps should not run as root$`, out)
+	assert.Regexp(t, `(?m)^CV-PIPELINE-002\s+Pipeline\s+warning\s+true\s+`, out)
+	assert.Regexp(t, `(?m)^ORG-TASK-001\s+Task\s+error\s+true\s+Tasks must have a team label$`, out)
+}
+
+func TestRulesExplain(t *testing.T) {
+	out, err := runRules(t, "explain", "CV-TASK-007")
+	require.NoError(t, err)
+	assert.Contains(t, out, "CV-TASK-007: Steps must not allow privilege escalation")
+	assert.Contains(t, out, "Fixable:  true")
+	assert.Contains(t, out, "Failing example:\n")
+	assert.Contains(t, out, "allowPrivilegeEscalation: true")
+	assert.Contains(t, out, "Passing example:\n")
+
+	_, err = runRules(t, "explain", "CV-TASK-999")
+	assert.EqualError(t, err, `unknown rule "CV-TASK-999", run rules list to see every rule`)
+}
+
+func TestGenRulesMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, cmd.GenRulesMarkdown(&out))
+
+	for _, r := range validator.Rules {
+		assert.Contains(t, out.String(), "\n## "+r.ID+"\n", r.ID)
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/garethjevans/component-validator/pkg/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "component-validator"
+	toolURI      = "https://github.com/garethjevans/component-validator"
+)
+
+// The subset of the SARIF 2.1.0 object model used to report diagnostics, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           *sarifProperties   `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifProperties struct {
+	Tags []string `json:"tags,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func sarifLevel(s validator.Severity) string {
+	switch s {
+	case validator.SeverityError:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func writeSARIF(w io.Writer, report *validator.Report) error {
+	driver := sarifDriver{
+		Name:           toolName,
+		InformationURI: toolURI,
+		Version:        version.Version,
+	}
+
+	ruleIndex := map[string]int{}
+	for _, r := range validator.Rules {
+		ruleIndex[r.ID] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, newSARIFRule(r))
+	}
+
+	newResult := func(d validator.Diagnostic) sarifResult {
+		i, ok := ruleIndex[d.Rule]
+		if !ok {
+			i = len(driver.Rules)
+			ruleIndex[d.Rule] = i
+			driver.Rules = append(driver.Rules, newSARIFRule(validator.Rule{ID: d.Rule, Kind: d.Kind, Severity: d.Severity, Description: d.Rule}))
+		}
+
+		result := sarifResult{
+			RuleID:    d.Rule,
+			RuleIndex: i,
+			Level:     sarifLevel(d.Severity),
+			Message:   sarifMessage{Text: d.Error()},
+		}
+
+		if d.Position.File != "" && d.Position.File != stdinName {
+			location := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: sarifURI(d.Position.File)},
+			}
+			if d.Position.Line > 0 {
+				location.Region = &sarifRegion{StartLine: d.Position.Line, StartColumn: d.Position.Column}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+
+		return result
+	}
+
+	results := []sarifResult{}
+	for _, d := range report.Diagnostics {
+		results = append(results, newResult(d))
+	}
+
+	// suppressed results are still reported so code scanning can close any
+	// alert previously raised for them.
+	for _, s := range report.Suppressed {
+		r := newResult(s.Diagnostic)
+		r.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: s.Reason}}
+		results = append(results, r)
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: driver},
+				Results: results,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifURI returns the URI of file, relative paths are kept relative so they
+// resolve against the root of the repository being scanned.
+func sarifURI(file string) string {
+	if filepath.IsAbs(file) {
+		return "file://" + filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(file)
+}
+
+func newSARIFRule(r validator.Rule) sarifRule {
+	help := r.Help
+	if help == "" {
+		help = r.Description
+	}
+
+	rule := sarifRule{
+		ID:                   r.ID,
+		ShortDescription:     sarifMessage{Text: r.Description},
+		FullDescription:      sarifMessage{Text: r.Description},
+		Help:                 sarifMessage{Text: help},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(r.Severity)},
+	}
+
+	if r.Kind != "" {
+		rule.Properties = &sarifProperties{Tags: []string{r.Kind}}
+	}
+
+	return rule
+}
@@ -1,277 +1,254 @@
 package cmd
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"reflect"
-	"regexp"
-	"strings"
 
-	"github.com/go-playground/locales/en"
-	ut "github.com/go-playground/universal-translator"
-	entranslations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/garethjevans/component-validator/pkg/validator"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"github.com/stoewer/go-strcase"
 	"go.uber.org/multierr"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 var (
-	Path string
+	Paths         []string
+	Include       []string
+	Exclude       []string
+	StrictParse   bool
+	FailOn        string
+	Output        string
+	Jobs          int
+	FailFast      bool
+	ConfigFile    string
+	BaselineFile  string
+	WriteBaseline string
+	Policies      []string
+	Fix           bool
+	DryRun        bool
 )
 
 // NewValidateCmd creates a new token command.
 func NewValidateCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "validate",
-		Short:        "Validates all components with the path supplied",
-		Long:         "",
-		Example:      "component-validator validate --path config/carvel.yaml",
+		Use:   "validate",
+		Short: "Validates all components with the path supplied",
+		Long:  "",
+		Example: `component-validator validate --path config/carvel.yaml
+component-validator validate --path config --exclude '**/test/**'
+component-validator validate --path 'config/**/*.yaml' --path other.yaml
+component-validator validate --path config --fix --dry-run
+component-validator validate --path config --policy policy/
+cat config/carvel.yaml | component-validator validate --path -`,
 		Aliases:      []string{"v"},
 		RunE:         validate,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 	}
 
-	cmd.Flags().StringVarP(&Path, "path", "p", "config/carvel.yaml", "The path to the component config to validate")
+	cmd.Flags().StringArrayVarP(&Paths, "path", "p", []string{"config/carvel.yaml"}, "The path to the component config to validate, may be a file, directory, glob pattern or - for stdin, can be repeated")
+	cmd.Flags().StringSliceVar(&Include, "include", []string{"*.yaml", "*.yml", "*.json"}, "Patterns of the files to validate when walking a directory or glob")
+	cmd.Flags().StringSliceVar(&Exclude, "exclude", nil, "Patterns of the files to skip")
+	cmd.Flags().BoolVar(&StrictParse, "strict-parse", false, "Fail if any document in the path cannot be decoded")
+	cmd.Flags().StringVar(&FailOn, "fail-on", string(validator.SeverityError), "The minimum severity that fails validation, either warning or error")
+	cmd.Flags().IntVarP(&Jobs, "jobs", "j", 0, "The number of documents to validate concurrently, defaults to GOMAXPROCS")
+	cmd.Flags().BoolVar(&FailFast, "fail-fast", false, "Stop validating at the first diagnostic that fails validation")
+	cmd.Flags().StringVar(&ConfigFile, "config", "", fmt.Sprintf("The file configuring the rules to check, defaults to %s if present", validator.DefaultConfigFile))
+	cmd.Flags().StringArrayVar(&Policies, "policy", nil, "A file or directory of Rego policies whose deny and warn rules are evaluated against each resource, can be repeated")
+	cmd.Flags().StringVar(&BaselineFile, "baseline", "", "The baseline of known violations to exclude, only new violations are reported")
+	cmd.Flags().StringVar(&WriteBaseline, "write-baseline", "", "Write the current violations to this baseline file rather than failing")
+	cmd.Flags().BoolVar(&Fix, "fix", false, "Edit the files to fix the violations that have a mechanical fix, preserving comments and formatting")
+	cmd.Flags().BoolVar(&DryRun, "dry-run", false, "With --fix, print a unified diff of the fixes rather than editing the files")
+	cmd.Flags().StringVarP(&Output, "output", "o", OutputText, fmt.Sprintf("The format to write the results in, one of %v, defaults to github when %s=true", outputs, githubActions))
 
 	return cmd
 }
 
+// Parse decodes each document within source and validates it, returning
+// each diagnostic with a severity of error, any document that cannot be
+// decoded is reported and skipped.
 func Parse(source []byte) error {
-	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(source), 1024)
-
-	var err error
-	for {
-		var u unstructured.Unstructured
-		if dec.Decode(&u) != nil {
-			break
-		}
-
-		switch u.GetKind() {
-		case "Task":
-			err = multierr.Append(err, ValidateTask(u))
-		case "Pipeline":
-			err = multierr.Append(err, ValidatePipeline(u))
-		case "Component":
-			err = multierr.Append(err, ValidateComponent(u))
-		default:
-			logrus.Infof("no validation specified for %s", u.GetKind())
-		}
-	}
-
-	return err
+	return ParseFile("", source)
 }
 
-func translate(kind string, name string, err error, translator ut.Translator) error {
-	if err != nil {
-		var translated error
-
-		errs := err.(validator.ValidationErrors)
+// ParseFile behaves as Parse, recording file as the source of any errors.
+func ParseFile(file string, source []byte) error {
+	v := validator.New(validator.Options{StrictParse: true})
 
-		for _, e := range errs {
-			formattedError := fmt.Errorf("%s/%s %s", kind, name, e.Translate(translator))
+	report, err := v.ValidateBytes(context.Background(), file, source)
+	if err != nil {
+		return err
+	}
 
-			if strings.Contains(formattedError.Error(), ".RunAsUser': is required") {
-				logrus.Warnf("Please ensure you meant to run as root! %s", formattedError)
-			} else {
-				translated = multierr.Append(translated, fmt.Errorf("%s/%s %s", kind, name, e.Translate(translator)))
-			}
+	for _, d := range report.Diagnostics {
+		if d.Severity != validator.SeverityError {
+			logrus.Warnf("%s", d.String())
 		}
-
-		return translated
 	}
-	return err
-}
 
-func getValidator() (*validator.Validate, ut.Translator, error) {
-	translator := en.New()
-	uni := ut.New(translator, translator)
+	var errs error
+	for _, d := range report.Errors() {
+		errs = multierr.Append(errs, d)
+	}
 
-	trans, _ := uni.GetTranslator("en")
-	validate := validator.New(validator.WithRequiredStructEnabled())
+	return errs
+}
 
-	err := entranslations.RegisterDefaultTranslations(validate, trans)
-	if err != nil {
-		return nil, nil, err
+func validate(cmd *cobra.Command, args []string) error {
+	threshold, err := validator.ParseSeverity(FailOn)
+	if err != nil || threshold == validator.SeverityInfo {
+		return fmt.Errorf("invalid --fail-on %q, expected one of [warning error]", FailOn)
 	}
 
-	err = multierr.Combine(
-		validate.RegisterValidation("kebab-case", ValidateKebabCase),
-		validate.RegisterValidation("contains-semver", ValidateContainsSemanticVersion),
-		validate.RegisterValidation("contains-catalog-label", ValidateContainsCatalogLabel),
-		validate.RegisterValidation("contains-all", ValidateContainsAll),
-		validate.RegisterValidation("not-contains-component", ValidateNotContainsComponent),
-		validate.RegisterValidation("compatible-nonroot", ValidateNonRoot),
-	)
-	if err != nil {
-		return nil, nil, fmt.Errorf(`failed to add custom validations": %s`, err)
+	if !cmd.Flags().Changed("output") && runningInGitHubActions() {
+		Output = OutputGitHub
 	}
 
-	err = validate.RegisterTranslation("required", trans, func(ut ut.Translator) error {
-		return ut.Add("required", "Key '{0}': is required", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("required", fe.StructNamespace())
-		return t
-	})
-	if err != nil {
-		return nil, nil, err
+	write, ok := outputs[Output]
+	if !ok {
+		return fmt.Errorf("invalid --output %q, expected one of %v", Output, outputs)
 	}
 
-	err = validate.RegisterTranslation("eq", trans, func(ut ut.Translator) error {
-		return ut.Add("eq", "Key '{0}': Expected {1} to equal {2}", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("eq", fe.StructNamespace(), fe.Value().(string), fe.Param())
-		return t
-	})
-	if err != nil {
-		return nil, nil, err
+	if Jobs < 0 {
+		return fmt.Errorf("invalid --jobs %d, expected a positive number", Jobs)
 	}
 
-	err = validate.RegisterTranslation("kebab-case", trans, func(ut ut.Translator) error {
-		return ut.Add("kebab-case", "Key '{0}': {1} does not appear to be in kebab-case", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("kebab-case", fe.StructNamespace(), fe.Value().(string))
-		return t
-	})
-	if err != nil {
-		return nil, nil, err
+	if DryRun && !Fix {
+		return fmt.Errorf("--dry-run can only be used with --fix")
 	}
 
-	err = validate.RegisterTranslation("contains-semver", trans, func(ut ut.Translator) error {
-		return ut.Add("contains-semver", "Key '{0}': {1} Does not end in a semantic version", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("contains-semver", fe.StructNamespace(), fe.Value().(string))
-		return t
-	})
-	if err != nil {
-		return nil, nil, err
+	if FailFast && WriteBaseline != "" {
+		return fmt.Errorf("--write-baseline cannot be used with --fail-fast")
 	}
 
-	err = validate.RegisterTranslation("contains-catalog-label", trans, func(ut ut.Translator) error {
-		return ut.Add("contains-catalog-label", "Key '{0}': Does not contain the key/value 'supply-chain.apps.tanzu.vmware.com/catalog: tanzu'", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("contains-catalog-label", fe.StructNamespace())
-		return t
-	})
-	if err != nil {
-		return nil, nil, err
+	var policy *validator.Policy
+	if len(Policies) > 0 {
+		policy, err = validator.LoadPolicy(Policies...)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = validate.RegisterTranslation("contains-all", trans, func(ut ut.Translator) error {
-		return ut.Add("contains-all", "Key '{0}': Must only contain the values [ALL]", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("contains-all", fe.StructNamespace())
-		return t
-	})
+	config, err := loadConfig(policy)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
 
-	err = validate.RegisterTranslation("not-contains-component", trans, func(ut ut.Translator) error {
-		return ut.Add("not-contains-component", "Key '{0}': Must not contain 'component'", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("not-contains-component", fe.StructNamespace())
-		return t
-	})
-	if err != nil {
-		return nil, nil, err
+	var baseline *validator.Baseline
+	if BaselineFile != "" {
+		baseline, err = validator.LoadBaseline(BaselineFile)
+		if err != nil {
+			return err
+		}
 	}
 
-	return validate, trans, nil
-}
-
-func validate(cmd *cobra.Command, args []string) error {
-	b, err := os.ReadFile(Path)
+	files, err := ExpandPaths(Paths, Include, Exclude)
 	if err != nil {
 		return err
 	}
 
-	err = Parse(b)
-
-	errors := multierr.Errors(err)
-	if len(errors) > 0 {
-		for _, e := range errors {
-			logrus.Errorf("%s", e)
+	var sources []validator.Source
+	for _, file := range files {
+		name, b, err := readFile(file, cmd.InOrStdin())
+		if err != nil {
+			return err
 		}
-	}
 
-	if err != nil {
-		return fmt.Errorf("finished with errors")
+		logrus.Debugf("validating %s", name)
+		sources = append(sources, validator.Source{File: name, Data: b})
 	}
 
-	return nil
-}
-
-func ValidateKebabCase(fl validator.FieldLevel) bool {
-	name := fl.Field().String()
-	return name == strcase.KebabCase(name)
-}
+	options := validator.Options{StrictParse: StrictParse, Jobs: Jobs, Config: config, Baseline: baseline, Policy: policy}
+	if FailFast {
+		options.FailFast = threshold
+	}
 
-func ValidateContainsSemanticVersion(fl validator.FieldLevel) bool {
-	re := regexp.MustCompile(`(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
-	name := fl.Field().String()
-	return re.MatchString(name)
-}
+	v := validator.New(options)
 
-func ValidateContainsCatalogLabel(fl validator.FieldLevel) bool {
-	numberOfEntries := len(fl.Field().MapKeys())
-	if numberOfEntries == 0 {
-		return false
+	if Fix {
+		if err := fix(cmd.Context(), v, cmd.OutOrStdout(), sources, DryRun); err != nil {
+			return err
+		}
 	}
 
-	m := fl.Field().Interface().(map[string]string)
-	v, ok := m["supply-chain.apps.tanzu.vmware.com/catalog"]
-	if !ok {
-		return false
+	report, err := v.ValidateSources(cmd.Context(), sources...)
+	if err != nil {
+		return err
 	}
 
-	return v == "tanzu"
-}
+	if DryRun {
+		// the diff is the output, the violations that remain are only logged
+		for _, d := range report.Diagnostics {
+			if d.Severity.AtLeast(threshold) {
+				logrus.Errorf("%s remains after fixing", d.String())
+			}
+		}
+		if report.Failed(threshold) {
+			return fmt.Errorf("finished with errors")
+		}
+		return nil
+	}
 
-func ValidateContainsAll(fl validator.FieldLevel) bool {
-	top := fl.Top()
+	if WriteBaseline != "" {
+		written := validator.NewBaseline(report)
+		if err := written.Save(WriteBaseline); err != nil {
+			return err
+		}
+		logrus.Infof("wrote %d violations to %s", len(written.Violations), WriteBaseline)
 
-	sc := reflect.Indirect(top).FieldByName("Spec").FieldByName("StepTemplate").FieldByName("SecurityContext")
-	runAsUser := sc.FieldByName("RunAsUser").Int()
+		written.Apply(report)
+	} else if baseline != nil && !(FailFast && report.Failed(threshold)) {
+		// a run that stopped early cannot tell which entries have been fixed
+		report.Diagnostics = append(report.Diagnostics, baseline.Stale(report)...)
+	}
 
-	isValid := true
-	m := fl.Field().Interface().([]string)
+	err = write(cmd.OutOrStdout(), report)
+	if err != nil {
+		return err
+	}
 
-	if runAsUser != 0 {
-		isValid = len(m) == 1 && m[0] == "ALL"
+	if report.Failed(threshold) {
+		return fmt.Errorf("finished with errors")
 	}
 
-	return isValid
+	return nil
 }
 
-func ValidateNotContainsComponent(fl validator.FieldLevel) bool {
-	name := fl.Field().String()
-	return !strings.Contains(name, "-component")
-}
+// loadConfig loads the --config file, falling back to the default config file
+// in the working directory when it exists. Rules of the policy are known rules.
+func loadConfig(policy *validator.Policy) (*validator.Config, error) {
+	file := ConfigFile
+	if file == "" {
+		if _, err := os.Stat(validator.DefaultConfigFile); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		file = validator.DefaultConfigFile
+	}
 
-func ValidateNonRoot(fl validator.FieldLevel) bool {
-	top := fl.Top()
+	logrus.Debugf("loading config from %s", file)
 
-	sc := reflect.Indirect(top).FieldByName("Spec").FieldByName("StepTemplate").FieldByName("SecurityContext")
+	config, err := validator.LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
 
-	runAsUser := sc.FieldByName("RunAsUser").Int()
-	nonRoot := fl.Field().Bool()
+	known := map[string]bool{}
+	if policy != nil {
+		for _, id := range policy.Rules() {
+			known[id] = true
+		}
+	}
 
-	if runAsUser == 0 && !nonRoot {
-		return true
-	} else if runAsUser != 0 && nonRoot {
-		return true
-	} else if runAsUser == 0 && nonRoot {
-		return false
-	} else if runAsUser != 0 && !nonRoot {
-		return false
+	for id := range config.Rules {
+		_, builtin := validator.LookupRule(id)
+		_, custom := config.CustomRule(id)
+		if !builtin && !custom && !known[id] {
+			logrus.Warnf("%s configures unknown rule %s", file, id)
+		}
 	}
 
-	return false
+	return config, nil
 }
@@ -1,11 +1,16 @@
 package cmd_test
 
 import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/garethjevans/component-validator/pkg/cmd"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
@@ -166,6 +171,28 @@ spec:
 			expectedErr: true,
 			errMessage:  "Task/my-pipeline Key 'Spec.StepTemplate.SecurityContext.SeccompProfile.Type': Expected Localhost to equal RuntimeDefault",
 		},
+		{
+			name: "v1 task - privilege escalation",
+			doc: `
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-pipeline
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: true
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+			expectedErr: true,
+			errMessage:  "Task/my-pipeline Key 'Spec.StepTemplate.SecurityContext.AllowPrivilegeEscalation': Expected true to equal false",
+		},
 		{
 			name: "v1 task - missing spec",
 			doc: `
@@ -206,6 +233,38 @@ metadata:
 			expectedErr: true,
 			errMessage:  "Task/my-pipeline Key 'APIVersion': Expected tekton.dev/v1beta1 to equal tekton.dev/v1; Task/my-pipeline Key 'Spec': is required",
 		},
+		{
+			name: "malformed document does not stop validation",
+			doc: `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: [my-pipeline
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: My_Pipeline
+`,
+			expectedErr: true,
+			errMessage:  "unable to decode document 1 (line 5): yaml: line 5: did not find expected ',' or ']'; Pipeline/My_Pipeline Key 'Metadata.Name': My_Pipeline does not appear to be in kebab-case",
+		},
+		{
+			name: "document without a kind",
+			doc: `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+---
+# a comment only document
+---
+metadata:
+  name: my-pipeline
+`,
+			expectedErr: true,
+			errMessage:  "unable to decode document 2 (line 9): Object 'Kind' is missing in '{\"metadata\":{\"name\":\"my-pipeline\"}}'",
+		},
 		{
 			name: "valid component",
 			doc: `---
@@ -292,3 +351,153 @@ spec:
 		})
 	}
 }
+
+func TestValidateFailFast(t *testing.T) {
+	doc := outputDoc + `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: Another_Pipeline
+`
+
+	out, err := runValidate(t, doc, "--output", "github", "--jobs", "1", "--fail-fast")
+	assert.EqualError(t, err, "finished with errors")
+	assert.Contains(t, out, "My_Pipeline")
+	assert.NotContains(t, out, "Another_Pipeline")
+}
+
+func TestValidateInvalidJobs(t *testing.T) {
+	_, err := runValidate(t, outputDoc, "--jobs", "-1")
+	assert.ErrorContains(t, err, "invalid --jobs -1")
+}
+
+func TestValidateConfig(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("rules:\n  CV-PIPELINE-002:\n    severity: warning\n"), 0o600))
+
+	out, err := runValidate(t, outputDoc, "--output", "github", "--config", config)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "::warning ")
+
+	_, err = runValidate(t, outputDoc, "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestValidateBaseline(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+
+	_, err := runValidate(t, outputDoc, "--write-baseline", baseline)
+	require.NoError(t, err)
+	assert.FileExists(t, baseline)
+
+	out, err := runValidate(t, outputDoc, "--output", "json", "--baseline", baseline)
+	require.NoError(t, err)
+	assert.Contains(t, out, `"baselined": 1`)
+
+	doc := `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: New_Pipeline
+`
+	out, err = runValidate(t, doc, "--output", "github", "--baseline", baseline)
+	assert.EqualError(t, err, "finished with errors")
+	assert.Contains(t, out, "title=CV-PIPELINE-002::Pipeline/New_Pipeline")
+	assert.Contains(t, out, "::warning file="+baseline+",title=CV-BASELINE-001::Pipeline/My_Pipeline")
+}
+
+func TestValidatePolicy(t *testing.T) {
+	policy := filepath.Join(t.TempDir(), "policy.rego")
+	require.NoError(t, os.WriteFile(policy, []byte(`package main
+
+warn[msg] {
+  input.kind == "Pipeline"
+  msg := "pipelines are deprecated"
+}
+`), 0o600))
+
+	out, err := runValidate(t, outputDoc, "--output", "github", "--policy", policy)
+	assert.EqualError(t, err, "finished with errors")
+	assert.Contains(t, out, "line=2,col=1,title=main.warn::Pipeline/My_Pipeline pipelines are deprecated")
+
+	_, err = runValidate(t, outputDoc, "--policy", t.TempDir())
+	assert.ErrorContains(t, err, "no policies found")
+}
+
+func TestValidateFix(t *testing.T) {
+	doc := `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0 # the name
+spec:
+  description: builds source
+  pipelineRun:
+    pipelineRef:
+      name: source
+`
+	path := filepath.Join(t.TempDir(), "carvel.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+
+	var out bytes.Buffer
+	c := cmd.NewValidateCmd()
+	c.SetArgs([]string{"--path", path, "--fix", "--dry-run"})
+	c.SetOut(&out)
+	require.NoError(t, c.Execute())
+
+	assert.Equal(t, "--- "+path+`
++++ `+path+`
+@@ -2,6 +2,8 @@
+ kind: Component
+ metadata:
+   name: source-1.0.0 # the name
++  labels:
++    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+ spec:
+   description: builds source
+   pipelineRun:
+`, out.String())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, doc, string(b))
+
+	c = cmd.NewValidateCmd()
+	c.SetArgs([]string{"--path", path, "--fix"})
+	c.SetOut(&bytes.Buffer{})
+	require.NoError(t, c.Execute())
+
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "  labels:\n    supply-chain.apps.tanzu.vmware.com/catalog: tanzu\n")
+}
+
+func TestValidateFixDryRunFailOn(t *testing.T) {
+	doc := `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+spec:
+  description: builds source
+  pipelineRun:
+    pipelineRef:
+      name: source
+`
+
+	out, err := runValidate(t, doc, "--fix", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu")
+
+	out, err = runValidate(t, doc+outputDoc, "--fix", "--dry-run")
+	assert.EqualError(t, err, "finished with errors")
+	assert.Contains(t, out, "+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu")
+}
+
+func TestValidateDryRunWithoutFix(t *testing.T) {
+	_, err := runValidate(t, outputDoc, "--dry-run")
+	assert.ErrorContains(t, err, "--dry-run can only be used with --fix")
+}
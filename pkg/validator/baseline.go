@@ -0,0 +1,165 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// RuleStaleBaseline is reported for each baseline entry that no longer
+// matches a diagnostic.
+const RuleStaleBaseline = "CV-BASELINE-001"
+
+const baselineVersion = 1
+
+// Baseline records known diagnostics so that only new diagnostics are
+// reported, allowing rules to be adopted without fixing every resource first.
+type Baseline struct {
+	Version    int             `json:"version"`
+	Violations []BaselineEntry `json:"violations"`
+
+	// file is the file the baseline was loaded from.
+	file string
+
+	once    sync.Once
+	entries map[BaselineEntry]bool
+}
+
+// BaselineEntry identifies a diagnostic independently of its position, so
+// entries still match when the document is edited.
+type BaselineEntry struct {
+	Kind  string `json:"kind,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Rule  string `json:"rule"`
+	Field string `json:"field,omitempty"`
+}
+
+func (e BaselineEntry) String() string {
+	s := e.Rule
+	if e.Kind != "" {
+		s = fmt.Sprintf("%s/%s %s", e.Kind, e.Name, s)
+	}
+	if e.Field != "" {
+		s = fmt.Sprintf("%s %s", s, e.Field)
+	}
+	return s
+}
+
+// baselinable reports whether the diagnostic can be recorded in a baseline.
+// A document that cannot be decoded has no kind, name or field to tell it
+// apart, so an entry for one would match every malformed document.
+func baselinable(d Diagnostic) bool {
+	return d.Rule != RuleDecode
+}
+
+func newBaselineEntry(d Diagnostic) BaselineEntry {
+	return BaselineEntry{Kind: d.Kind, Name: d.Name, Rule: d.Rule, Field: d.Field}
+}
+
+// NewBaseline creates a baseline of every diagnostic in the report, including
+// those already matched by a baseline. Documents that cannot be decoded are
+// never baselined.
+func NewBaseline(report *Report) *Baseline {
+	seen := map[BaselineEntry]bool{}
+	b := &Baseline{Version: baselineVersion, Violations: []BaselineEntry{}}
+
+	for _, diagnostics := range [][]Diagnostic{report.Diagnostics, report.Baselined} {
+		for _, d := range diagnostics {
+			if !baselinable(d) {
+				continue
+			}
+			e := newBaselineEntry(d)
+			if !seen[e] {
+				seen[e] = true
+				b.Violations = append(b.Violations, e)
+			}
+		}
+	}
+
+	sort.Slice(b.Violations, func(i, j int) bool {
+		return b.Violations[i].String() < b.Violations[j].String()
+	})
+
+	return b
+}
+
+// LoadBaseline reads a baseline written by Save.
+func LoadBaseline(file string) (*Baseline, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Baseline{file: file}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", file, err)
+	}
+
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("invalid baseline %s: unsupported version %d", file, b.Version)
+	}
+
+	return b, nil
+}
+
+// Save writes the baseline to file.
+func (b *Baseline) Save(file string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+// Apply moves each diagnostic of the report matching an entry of the
+// baseline to Report.Baselined, it is safe for concurrent use.
+func (b *Baseline) Apply(report *Report) {
+	b.once.Do(func() {
+		b.entries = map[BaselineEntry]bool{}
+		for _, e := range b.Violations {
+			b.entries[e] = true
+		}
+	})
+
+	var diagnostics []Diagnostic
+	for _, d := range report.Diagnostics {
+		if baselinable(d) && b.entries[newBaselineEntry(d)] {
+			report.Baselined = append(report.Baselined, d)
+			continue
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	report.Diagnostics = diagnostics
+}
+
+// Stale returns a warning for each entry of the baseline not matched by the
+// diagnostics baselined in the report, they have since been fixed and can be
+// removed.
+func (b *Baseline) Stale(report *Report) []Diagnostic {
+	matched := map[BaselineEntry]bool{}
+	for _, d := range report.Baselined {
+		matched[newBaselineEntry(d)] = true
+	}
+
+	var stale []Diagnostic
+	for _, e := range b.Violations {
+		if matched[e] {
+			continue
+		}
+
+		stale = append(stale, Diagnostic{
+			Kind:     e.Kind,
+			Name:     e.Name,
+			Field:    e.Field,
+			Rule:     RuleStaleBaseline,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s is no longer reported, remove it from the baseline", e),
+			Position: Position{File: b.file},
+		})
+	}
+
+	return stale
+}
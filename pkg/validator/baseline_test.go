@@ -0,0 +1,89 @@
+package validator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	source := validator.Source{File: "carvel.yaml", Data: pipelines("My_Pipeline", "Other_Pipeline")}
+
+	report, err := validator.New(validator.Options{}).ValidateSources(context.Background(), source)
+	require.NoError(t, err)
+	require.Len(t, report.Diagnostics, 2)
+
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, validator.NewBaseline(report).Save(file))
+
+	baseline, err := validator.LoadBaseline(file)
+	require.NoError(t, err)
+	assert.Equal(t, []validator.BaselineEntry{
+		{Kind: "Pipeline", Name: "My_Pipeline", Rule: "CV-PIPELINE-002", Field: "metadata.name"},
+		{Kind: "Pipeline", Name: "Other_Pipeline", Rule: "CV-PIPELINE-002", Field: "metadata.name"},
+	}, baseline.Violations)
+
+	// one violation is fixed and another introduced
+	source.Data = pipelines("My_Pipeline", "other-pipeline", "New_Pipeline")
+
+	report, err = validator.New(validator.Options{Baseline: baseline}).ValidateSources(context.Background(), source)
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, "New_Pipeline", report.Diagnostics[0].Name)
+	require.Len(t, report.Baselined, 1)
+	assert.Equal(t, "My_Pipeline", report.Baselined[0].Name)
+
+	stale := baseline.Stale(report)
+	require.Len(t, stale, 1)
+	assert.Equal(t, validator.RuleStaleBaseline, stale[0].Rule)
+	assert.Equal(t, validator.SeverityWarning, stale[0].Severity)
+	assert.Equal(t, "Other_Pipeline", stale[0].Name)
+	assert.Equal(t, file, stale[0].Position.File)
+}
+
+func TestBaselineDecodeErrors(t *testing.T) {
+	source := validator.Source{File: "carvel.yaml", Data: append(pipelines("My_Pipeline"), "---\nkind: [broken\n"...)}
+
+	report, err := validator.New(validator.Options{}).ValidateSources(context.Background(), source)
+	require.NoError(t, err)
+	require.Len(t, report.Diagnostics, 2)
+
+	baseline := validator.NewBaseline(report)
+	assert.Equal(t, []validator.BaselineEntry{
+		{Kind: "Pipeline", Name: "My_Pipeline", Rule: "CV-PIPELINE-002", Field: "metadata.name"},
+	}, baseline.Violations)
+
+	// an entry for a decode error, as written by an earlier version, matches
+	// no diagnostic and is reported as stale
+	baseline.Violations = append(baseline.Violations, validator.BaselineEntry{Rule: validator.RuleDecode})
+
+	other := validator.Source{File: "other.yaml", Data: []byte("kind: [broken\n")}
+	report, err = validator.New(validator.Options{Baseline: baseline}).ValidateSources(context.Background(), source, other)
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, d.Position.File+" "+d.Rule)
+	}
+	assert.Equal(t, []string{"carvel.yaml CV-YAML-001", "other.yaml CV-YAML-001"}, diagnostics)
+	require.Len(t, report.Baselined, 1)
+
+	stale := baseline.Stale(report)
+	require.Len(t, stale, 1)
+	assert.Contains(t, stale[0].Message, validator.RuleDecode)
+}
+
+func TestLoadBaselineInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"version": 2, "violations": []}`), 0o600))
+
+	_, err := validator.LoadBaseline(file)
+	assert.ErrorContains(t, err, "unsupported version 2")
+}
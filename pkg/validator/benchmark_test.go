@@ -0,0 +1,139 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+)
+
+// bundle creates a multi-document input of n resources, similar in size to a
+// release bundle.
+func bundle(n int) []byte {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&b, `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: task-%d
+spec:
+  params:
+  - name: source-url
+  results:
+  - name: image-ref
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`, i)
+		case 1:
+			fmt.Fprintf(&b, `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline-%d
+`, i)
+		default:
+			fmt.Fprintf(&b, `---
+apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-%d-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: builds source
+`, i)
+		}
+	}
+	return []byte(b.String())
+}
+
+func BenchmarkValidateBytes(b *testing.B) {
+	for _, n := range []int{1, 100, 2000} {
+		source := bundle(n)
+		b.Run(fmt.Sprintf("documents=%d", n), func(b *testing.B) {
+			v := validator.New(validator.Options{})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := v.ValidateBytes(context.Background(), "bundle.yaml", source); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkValidateDocuments validates each document of a bundle on its own,
+// comparing the shared validator and translator against building them for
+// every document, as was done before they were shared.
+func BenchmarkValidateDocuments(b *testing.B) {
+	documents := strings.SplitAfter(string(bundle(2000)), "---\n")[1:]
+
+	for _, rebuild := range []bool{false, true} {
+		name := "validator=shared"
+		if rebuild {
+			name = "validator=per-document"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			v := validator.New(validator.Options{Jobs: 1})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, doc := range documents {
+					if rebuild {
+						validator.ResetSharedValidator()
+					}
+					if _, err := v.ValidateBytes(context.Background(), "bundle.yaml", []byte(doc)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidateBytesParallel(b *testing.B) {
+	source := bundle(100)
+	v := validator.New(validator.Options{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := v.ValidateBytes(context.Background(), "bundle.yaml", source); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkValidateSources compares validating a bundle with a single job, as
+// with -j 1, against a pool of jobs. Run with -cpu to vary the CPUs available.
+func BenchmarkValidateSources(b *testing.B) {
+	source := validator.Source{File: "bundle.yaml", Data: bundle(2000)}
+	for _, jobs := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			v := validator.New(validator.Options{Jobs: jobs})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := v.ValidateSources(context.Background(), source); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// CustomRule is a rule defined by a CEL expression that must evaluate to true
+// for every resource the rule matches, e.g.
+//
+//	customRules:
+//	- id: ORG-TASK-001
+//	  match:
+//	    kinds: [Task]
+//	  expression: "'app.kubernetes.io/version' in object.metadata.?labels.orValue({})"
+//	  message: "{{ .metadata.name }} must have an app.kubernetes.io/version label"
+//	  field: metadata.labels
+type CustomRule struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Severity    Severity `json:"severity,omitempty"`
+	Match       Match    `json:"match"`
+	// Expression is evaluated with the resource as object.
+	Expression string `json:"expression"`
+	// Message is a text/template rendered with the resource when the
+	// expression is false, the description is used when empty.
+	Message string `json:"message,omitempty"`
+	// Field is the path of the field reported against, e.g. metadata.labels.
+	Field string `json:"field,omitempty"`
+}
+
+// Match selects the resources a CustomRule is evaluated against.
+type Match struct {
+	// APIGroups restricts the rule to resources in the given groups, every
+	// group matches when empty.
+	APIGroups []string `json:"apiGroups,omitempty"`
+	Kinds     []string `json:"kinds"`
+}
+
+func (m Match) matches(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	return contains(m.Kinds, gvk.Kind) && (len(m.APIGroups) == 0 || contains(m.APIGroups, gvk.Group))
+}
+
+// celRule is a CustomRule compiled so it can be evaluated against each
+// resource, it is a ResourceValidator.
+type celRule struct {
+	CustomRule
+	program cel.Program
+	message *template.Template
+}
+
+func newCELEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.OptionalTypes(),
+		ext.Strings(),
+	)
+}
+
+func compileCustomRule(env *cel.Env, r CustomRule) (*celRule, error) {
+	if r.ID == "" {
+		return nil, fmt.Errorf("custom rule has no id")
+	}
+	if _, ok := LookupRule(r.ID); ok {
+		return nil, fmt.Errorf("custom rule %s has the id of a built in rule", r.ID)
+	}
+	if len(r.Match.Kinds) == 0 {
+		return nil, fmt.Errorf("custom rule %s does not match any kinds", r.ID)
+	}
+	if r.Severity == "" {
+		r.Severity = SeverityError
+	}
+	if _, err := ParseSeverity(string(r.Severity)); err != nil {
+		return nil, fmt.Errorf("custom rule %s: %w", r.ID, err)
+	}
+
+	ast, issues := env.Compile(r.Expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("custom rule %s: %w", r.ID, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("custom rule %s: expression must evaluate to a bool, not %s", r.ID, ast.OutputType())
+	}
+
+	program, err := env.Program(ast, cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, fmt.Errorf("custom rule %s: %w", r.ID, err)
+	}
+
+	message := r.Message
+	if message == "" {
+		message = r.Description
+	}
+	if message == "" {
+		message = fmt.Sprintf("Expected %s to be true", r.Expression)
+	}
+
+	tmpl, err := template.New(r.ID).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("custom rule %s: invalid message: %w", r.ID, err)
+	}
+
+	return &celRule{CustomRule: r, program: program, message: tmpl}, nil
+}
+
+// Validate evaluates the expression against the resource, reporting a
+// diagnostic when it is not true.
+func (r *celRule) Validate(ctx context.Context, u *unstructured.Unstructured) []Diagnostic {
+	out, _, err := r.program.ContextEval(ctx, map[string]interface{}{"object": u.Object})
+	if err != nil && ctx.Err() != nil {
+		// validation was cancelled, the resource is not reported.
+		return nil
+	}
+	if err != nil {
+		return []Diagnostic{r.diagnostic(*u, fmt.Sprintf("unable to evaluate %s: %s", r.Expression, err))}
+	}
+
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return []Diagnostic{r.diagnostic(*u, fmt.Sprintf("expected %s to evaluate to a bool, not %v", r.Expression, out.Type()))}
+	}
+	if passed {
+		return nil
+	}
+
+	var message strings.Builder
+	if err := r.message.Execute(&message, u.Object); err != nil {
+		return []Diagnostic{r.diagnostic(*u, fmt.Sprintf("unable to render the message: %s", err))}
+	}
+
+	return []Diagnostic{r.diagnostic(*u, message.String())}
+}
+
+func (r *celRule) diagnostic(u unstructured.Unstructured, message string) Diagnostic {
+	d := newDiagnostic(u, r.ID, r.Severity, message)
+	d.Field = r.Field
+	return d
+}
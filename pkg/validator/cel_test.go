@@ -0,0 +1,196 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const customRules = `customRules:
+- id: ORG-TASK-001
+  severity: warning
+  match:
+    kinds: [Task]
+  expression: "'app.kubernetes.io/version' in object.metadata.?labels.orValue({})"
+  message: "{{ .metadata.name }} must have an app.kubernetes.io/version label"
+  field: metadata.labels
+- id: ORG-PIPELINE-001
+  match:
+    apiGroups: [tekton.dev]
+    kinds: [Pipeline]
+  expression: "!has(object.spec.timeouts) || duration(object.spec.timeouts.pipeline) < duration('2h')"
+  message: "Pipelines must time out in under 2h"
+  field: spec.timeouts.pipeline
+- id: ORG-CONFIGMAP-001
+  match:
+    kinds: [ConfigMap]
+  expression: "object.metadata.name.startsWith('org-')"
+`
+
+func TestCustomRules(t *testing.T) {
+	doc := `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+spec:
+  timeouts:
+    pipeline: 3h
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: org-config
+`
+
+	config, err := validator.LoadConfig(writeConfig(t, customRules))
+	require.NoError(t, err)
+
+	report, err := validator.New(validator.Options{Config: config}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	assert.Equal(t, []validator.Diagnostic{
+		{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+			Name:       "my-task",
+			Field:      "metadata.labels",
+			Rule:       "ORG-TASK-001",
+			Severity:   validator.SeverityWarning,
+			Message:    "my-task must have an app.kubernetes.io/version label",
+			Position:   validator.Position{File: "carvel.yaml", Document: 1, Line: 4, Column: 1},
+		},
+		{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Pipeline",
+			Name:       "my-pipeline",
+			Field:      "spec.timeouts.pipeline",
+			Rule:       "ORG-PIPELINE-001",
+			Severity:   validator.SeverityError,
+			Message:    "Pipelines must time out in under 2h",
+			Position:   validator.Position{File: "carvel.yaml", Document: 2, Line: 24, Column: 5},
+		},
+	}, report.Diagnostics)
+
+	assert.Len(t, report.Resources, 3)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestCustomRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "syntax error",
+			config: "customRules:\n- id: ORG-001\n  match: {kinds: [Task]}\n  expression: \"object.metadata.name ==\"\n",
+			err:    "custom rule ORG-001: ERROR",
+		},
+		{
+			name:   "not a bool",
+			config: "customRules:\n- id: ORG-001\n  match: {kinds: [Task]}\n  expression: \"'name'\"\n",
+			err:    "custom rule ORG-001: expression must evaluate to a bool, not string",
+		},
+		{
+			name:   "built in id",
+			config: "customRules:\n- id: CV-TASK-001\n  match: {kinds: [Task]}\n  expression: \"true\"\n",
+			err:    "custom rule CV-TASK-001 has the id of a built in rule",
+		},
+		{
+			name:   "no kinds",
+			config: "customRules:\n- id: ORG-001\n  expression: \"true\"\n",
+			err:    "custom rule ORG-001 does not match any kinds",
+		},
+		{
+			name:   "duplicate id",
+			config: "customRules:\n- id: ORG-001\n  match: {kinds: [Task]}\n  expression: \"true\"\n- id: ORG-001\n  match: {kinds: [Task]}\n  expression: \"true\"\n",
+			err:    "custom rule ORG-001 is defined more than once",
+		},
+		{
+			name:   "invalid message",
+			config: "customRules:\n- id: ORG-001\n  match: {kinds: [Task]}\n  expression: \"true\"\n  message: \"{{ .metadata\"\n",
+			err:    "custom rule ORG-001: invalid message",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validator.LoadConfig(writeConfig(t, tc.config))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestCustomRulesInvalidValidator(t *testing.T) {
+	// the config is built in code, so it is not validated before use
+	config := &validator.Config{CustomRules: []validator.CustomRule{{
+		ID:         "ORG-001",
+		Match:      validator.Match{Kinds: []string{"Pipeline"}},
+		Expression: "object.metadata.name ==",
+	}}}
+	v := validator.New(validator.Options{Config: config})
+
+	_, err := v.ValidateBytes(context.Background(), "carvel.yaml", []byte("apiVersion: tekton.dev/v1\nkind: Pipeline\nmetadata:\n  name: my-pipeline\n"))
+	assert.ErrorContains(t, err, "custom rule ORG-001: ERROR")
+
+	_, err = v.ValidateObject(context.Background(), unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "Pipeline",
+		"metadata":   map[string]interface{}{"name": "my-pipeline"},
+	}})
+	assert.ErrorContains(t, err, "custom rule ORG-001: ERROR")
+}
+
+func TestCustomRulesCancelled(t *testing.T) {
+	var items []string
+	for i := 1; i <= 100; i++ {
+		items = append(items, fmt.Sprint(i))
+	}
+	list := "[" + strings.Join(items, ", ") + "]"
+
+	// the comprehension never fails, but is still evaluating when cancelled
+	config := &validator.Config{CustomRules: []validator.CustomRule{{
+		ID:         "ORG-PIPELINE-001",
+		Match:      validator.Match{Kinds: []string{"Pipeline"}},
+		Expression: fmt.Sprintf("%[1]s.all(a, %[1]s.all(b, %[1]s.all(c, a + b + c > 0)))", list),
+	}}}
+	require.NoError(t, config.Validate())
+
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "Pipeline",
+		"metadata":   map[string]interface{}{"name": "my-pipeline"},
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := validator.New(validator.Options{Config: config}).ValidateObject(ctx, u)
+	require.NoError(t, err)
+	assert.Empty(t, report.Diagnostics)
+}
@@ -1,29 +1,33 @@
-package cmd
+package validator
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 )
 
-func ValidateComponent(u unstructured.Unstructured) error {
+// ValidateComponent validates a supply chain Component, it is registered for
+// every version of supply-chain.apps.tanzu.vmware.com/Component by
+// DefaultRegistry.
+func ValidateComponent(ctx context.Context, u *unstructured.Unstructured) []Diagnostic {
 	validate, translator, err := getValidator()
 	if err != nil {
-		return err
+		return []Diagnostic{newDiagnostic(*u, RuleStructure, SeverityError, err.Error())}
 	}
 
 	fields := &struct {
-		APIVersion string `json:"apiVersion" validate:"required,eq=supply-chain.apps.tanzu.vmware.com/v1alpha1"`
+		APIVersion string `json:"apiVersion" validate:"required,api-version=CV-COMPONENT-001"`
 		Kind       string `json:"kind" validate:"required,eq=Component"`
 		Metadata   struct {
 			Name   string            `json:"name" validate:"required,kebab-case,contains-semver,not-contains-component"`
-			Labels map[string]string `json:"labels" validate:"contains-catalog-label"`
+			Labels map[string]string `json:"labels" validate:"contains-catalog-label=CV-COMPONENT-005"`
 		} `json:"metadata"`
 		Spec struct {
 			Description string `json:"description" validate:"required"`
 			PipelineRun struct {
 				Params []struct {
 					Name string `json:"name" validate:"required,kebab-case"`
-				}
+				} `json:"params" validate:"dive"`
 				PipelineRef struct {
 					Name string `json:"name" validate:"required,kebab-case"`
 				} `json:"pipelineRef" validate:"required"`
@@ -31,10 +35,9 @@ func ValidateComponent(u unstructured.Unstructured) error {
 		} `json:"spec" validate:"required"`
 	}{}
 
-	err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &fields)
-	if err != nil {
-		return err
+	if diagnostics := convert(*u, &fields); diagnostics != nil {
+		return diagnostics
 	}
 
-	return translate(fields.Kind, fields.Metadata.Name, validate.Struct(fields), translator)
+	return translate(ctx, *u, fields, validate.StructCtx(ctx, fields), translator)
 }
@@ -0,0 +1,223 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/multierr"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultConfigFile is the configuration loaded from the working directory
+// when no other configuration is specified.
+const DefaultConfigFile = ".component-validator.yaml"
+
+// Config customises the rules checked by a Validator, e.g.
+//
+//	rules:
+//	  CV-TASK-004:
+//	    enabled: false
+//	  CV-COMPONENT-005:
+//	    severity: warning
+//	    params:
+//	      values: [tanzu, partner]
+type Config struct {
+	// Rules are keyed by the ID of the rule they configure.
+	Rules map[string]RuleConfig `json:"rules,omitempty"`
+	// CustomRules are additional rules defined by CEL expressions.
+	CustomRules []CustomRule `json:"customRules,omitempty"`
+
+	once     sync.Once
+	compiled []*celRule
+	err      error
+}
+
+// RuleConfig configures a single rule, any value left unset keeps the default
+// of the rule.
+type RuleConfig struct {
+	// Enabled disables the rule when false, or enables an optional rule when
+	// true.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Severity overrides the severity the rule is reported with.
+	Severity Severity `json:"severity,omitempty"`
+	// Params are keyed by the name of the rule parameter they set.
+	Params map[string]Values `json:"params,omitempty"`
+}
+
+// Values are the values of a rule parameter, a single value may be written as
+// a string rather than a list.
+type Values []string
+
+// UnmarshalJSON accepts either a string or a list of strings.
+func (v *Values) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*v = Values{s}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return fmt.Errorf("expected a string or a list of strings: %w", err)
+	}
+	*v = values
+	return nil
+}
+
+// LoadConfig reads the configuration from file, rejecting any unknown field.
+func LoadConfig(file string) (*Config, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", file, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", file, err)
+	}
+
+	return c, nil
+}
+
+// Validate checks the severity and parameters of each configured rule and
+// compiles every custom rule. Rules that are not built in may be reported by a
+// custom ResourceValidator so only the parameters of built in rules are
+// checked. A Validator returns the same error for a custom rule that fails to
+// compile when validating, Validate reports it before any document is read.
+func (c *Config) Validate() error {
+	if _, err := c.custom(); err != nil {
+		return err
+	}
+
+	for id, rc := range c.Rules {
+		if rc.Severity != "" {
+			if _, err := ParseSeverity(string(rc.Severity)); err != nil {
+				return fmt.Errorf("rule %s: %w", id, err)
+			}
+		}
+
+		rule, ok := LookupRule(id)
+		if !ok {
+			continue
+		}
+
+		for name, values := range rc.Params {
+			p, ok := rule.param(name)
+			if !ok {
+				return fmt.Errorf("rule %s has no parameter %q", id, name)
+			}
+			if len(values) == 0 || (!p.Multiple && len(values) > 1) {
+				return fmt.Errorf("rule %s parameter %q expects %s", id, name, p.expects())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Enabled reports whether the rule with the given id is checked, optional
+// rules are only checked when enabled.
+func (c *Config) Enabled(id string) bool {
+	if c != nil {
+		if rc, ok := c.Rules[id]; ok && rc.Enabled != nil {
+			return *rc.Enabled
+		}
+	}
+	rule, _ := LookupRule(id)
+	return !rule.Optional
+}
+
+// Severity returns the severity the rule with the given id is reported with,
+// or the severity reported by the check when it is not overridden.
+func (c *Config) Severity(id string, reported Severity) Severity {
+	if c == nil || c.Rules[id].Severity == "" {
+		return reported
+	}
+	return c.Rules[id].Severity
+}
+
+// Param returns the values of the named parameter of the rule with the given
+// id, falling back to the default of the parameter. An empty list, which
+// Validate rejects, also falls back to the default so a Config that has not
+// been validated still has at least one value for each parameter.
+func (c *Config) Param(id string, name string) []string {
+	if c != nil {
+		if values, ok := c.Rules[id].Params[name]; ok && len(values) > 0 {
+			return values
+		}
+	}
+
+	rule, _ := LookupRule(id)
+	p, _ := rule.param(name)
+	return p.Default
+}
+
+// custom returns the compiled custom rules, they are compiled once.
+func (c *Config) custom() ([]*celRule, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	c.once.Do(func() {
+		if len(c.CustomRules) == 0 {
+			return
+		}
+
+		env, err := newCELEnv()
+		if err != nil {
+			c.err = err
+			return
+		}
+
+		seen := map[string]bool{}
+		for _, r := range c.CustomRules {
+			if seen[r.ID] {
+				c.err = multierr.Append(c.err, fmt.Errorf("custom rule %s is defined more than once", r.ID))
+				continue
+			}
+			seen[r.ID] = true
+
+			compiled, err := compileCustomRule(env, r)
+			if err != nil {
+				c.err = multierr.Append(c.err, err)
+				continue
+			}
+			c.compiled = append(c.compiled, compiled)
+		}
+	})
+
+	return c.compiled, c.err
+}
+
+// CustomRule returns the custom rule with the given id.
+func (c *Config) CustomRule(id string) (CustomRule, bool) {
+	if c != nil {
+		for _, r := range c.CustomRules {
+			if r.ID == id {
+				return r, true
+			}
+		}
+	}
+	return CustomRule{}, false
+}
+
+type configKey struct{}
+
+// withConfig returns a context that carries c to the validations of each rule.
+func withConfig(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, c)
+}
+
+// configFrom returns the Config carried by ctx, a nil Config applies the
+// defaults of every rule.
+func configFrom(ctx context.Context) *Config {
+	c, _ := ctx.Value(configKey{}).(*Config)
+	return c
+}
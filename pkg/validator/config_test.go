@@ -0,0 +1,199 @@
+package validator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), validator.DefaultConfigFile)
+	require.NoError(t, os.WriteFile(file, []byte(config), 0o600))
+	return file
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "valid",
+			config: `rules:
+  CV-TASK-004:
+    enabled: false
+  CV-COMPONENT-005:
+    severity: warning
+    params:
+      label: example.com/catalog
+      values: [tanzu, partner]
+  CUSTOM-001:
+    severity: info
+`,
+		},
+		{
+			name:   "unknown field",
+			config: "rule: {}\n",
+			err:    `unknown field "rule"`,
+		},
+		{
+			name:   "unknown severity",
+			config: "rules:\n  CV-TASK-004:\n    severity: fatal\n",
+			err:    `rule CV-TASK-004: unknown severity "fatal"`,
+		},
+		{
+			name:   "unknown param",
+			config: "rules:\n  CV-TASK-004:\n    params:\n      user: [1000]\n",
+			err:    `rule CV-TASK-004 has no parameter "user"`,
+		},
+		{
+			name:   "multiple values for a single param",
+			config: "rules:\n  CV-TASK-001:\n    params:\n      minVersion: [v1beta1, v1]\n",
+			err:    `rule CV-TASK-001 parameter "minVersion" expects a single value`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := validator.LoadConfig(writeConfig(t, tc.config))
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.False(t, config.Enabled("CV-TASK-004"))
+			assert.True(t, config.Enabled("CV-TASK-005"))
+			assert.Equal(t, validator.SeverityWarning, config.Severity("CV-COMPONENT-005", validator.SeverityError))
+			assert.Equal(t, []string{"tanzu", "partner"}, config.Param("CV-COMPONENT-005", "values"))
+			assert.Equal(t, []string{"example.com/catalog"}, config.Param("CV-COMPONENT-005", "label"))
+			assert.Equal(t, []string{"RuntimeDefault"}, config.Param("CV-TASK-008", "types"))
+		})
+	}
+}
+
+func TestValidateWithConfig(t *testing.T) {
+	doc := `---
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: false
+      runAsUser: 0
+      seccompProfile:
+        type: Localhost
+---
+apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    example.com/catalog: partner
+spec:
+  description: builds source
+  pipelineRun:
+    pipelineRef:
+      name: source
+`
+
+	config, err := validator.LoadConfig(writeConfig(t, `rules:
+  CV-TASK-001:
+    params:
+      minVersion: v1beta1
+  CV-TASK-004:
+    enabled: false
+  CV-TASK-008:
+    severity: warning
+    params:
+      types: [RuntimeDefault, Unconfined]
+  CV-COMPONENT-005:
+    params:
+      label: example.com/catalog
+      values: [tanzu, partner]
+`))
+	require.NoError(t, err)
+
+	report, err := validator.New(validator.Options{Config: config}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, "CV-TASK-008", report.Diagnostics[0].Rule)
+	assert.Equal(t, validator.SeverityWarning, report.Diagnostics[0].Severity)
+	assert.Equal(t, "Key 'Spec.StepTemplate.SecurityContext.SeccompProfile.Type': Expected Localhost to equal RuntimeDefault or Unconfined", report.Diagnostics[0].Message)
+
+	report, err = validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var rules []string
+	for _, d := range report.Diagnostics {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{"CV-TASK-001", "CV-TASK-004", "CV-TASK-008", "CV-COMPONENT-005"}, rules)
+}
+
+func TestValidateWithEmptyParams(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: Localhost
+---
+apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+spec:
+  description: builds source
+  pipelineRun:
+    pipelineRef:
+      name: source
+`
+
+	// the config is not loaded, so it is not validated
+	config := &validator.Config{Rules: map[string]validator.RuleConfig{
+		"CV-TASK-001":      {Params: map[string]validator.Values{"minVersion": {}}},
+		"CV-TASK-008":      {Params: map[string]validator.Values{"types": {}}},
+		"CV-COMPONENT-005": {Params: map[string]validator.Values{"label": {}, "values": {}}},
+	}}
+	v := validator.New(validator.Options{Config: config})
+
+	report, err := v.ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var rules []string
+	for _, d := range report.Diagnostics {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{"CV-TASK-008", "CV-COMPONENT-005"}, rules)
+
+	fixed, _, err := v.Fix(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+	assert.Contains(t, string(fixed), "type: RuntimeDefault")
+	assert.Contains(t, string(fixed), "supply-chain.apps.tanzu.vmware.com/catalog: tanzu")
+}
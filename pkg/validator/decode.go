@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	separator  = "---"
+	whitespace = " \t\r\n"
+)
+
+var lineNumber = regexp.MustCompile(`line (\d+)`)
+
+// DecodeError is returned when a document within the source cannot be decoded.
+type DecodeError struct {
+	Position
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("unable to decode document %d (line %d): %s", e.Document, e.Line, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+type document struct {
+	// Index is the 1-based index of the document within the source.
+	Index int
+	// Line is the 1-based line within the source the document starts on.
+	Line int
+	Data []byte
+}
+
+// splitDocuments splits a multi-document YAML source on its separators,
+// recording where each document starts so errors can be reported against
+// the original source. A stream of JSON objects, which has no separators, is
+// split on the objects instead.
+func splitDocuments(source []byte) []document {
+	if documents := splitJSON(source); documents != nil {
+		return documents
+	}
+	return splitYAML(source)
+}
+
+// splitYAML splits a source on its "---" separators.
+func splitYAML(source []byte) []document {
+	var documents []document
+
+	current := document{Line: 1}
+	add := func() {
+		if !isEmpty(current.Data) {
+			current.Index = len(documents) + 1
+			documents = append(documents, current)
+		}
+	}
+
+	lines := bytes.SplitAfter(source, []byte("\n"))
+	for i, line := range lines {
+		if isSeparator(line) {
+			add()
+			current = document{Line: i + 2}
+			continue
+		}
+		current.Data = append(current.Data, line...)
+	}
+	add()
+
+	return documents
+}
+
+// splitJSON splits a source starting with a JSON object into one document per
+// object. It returns nil when the source is not JSON, as a single object is
+// also valid YAML. Anything following the objects that is not JSON, e.g. YAML
+// documents after a separator, is split as YAML, numbering its documents and
+// lines after the objects.
+func splitJSON(source []byte) []document {
+	if !bytes.HasPrefix(bytes.TrimLeft(source, whitespace), []byte("{")) {
+		return nil
+	}
+
+	var documents []document
+	dec := json.NewDecoder(bytes.NewReader(source))
+	for {
+		start := int(dec.InputOffset())
+		start += len(source[start:]) - len(bytes.TrimLeft(source[start:], whitespace))
+		if start == len(source) {
+			return documents
+		}
+
+		doc := document{Index: len(documents) + 1, Line: bytes.Count(source[:start], []byte("\n")) + 1}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if len(documents) == 0 {
+				return nil
+			}
+			n := len(documents)
+			for _, rest := range splitYAML(source[start:]) {
+				rest.Index += n
+				rest.Line += doc.Line - 1
+				documents = append(documents, rest)
+			}
+			return documents
+		}
+
+		doc.Data = raw
+		documents = append(documents, doc)
+	}
+}
+
+func isSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, []byte(separator)) {
+		return false
+	}
+	trimmed := strings.TrimSpace(string(line[len(separator):]))
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// isEmpty reports whether a document contains nothing but whitespace and comments.
+func isEmpty(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func decode(file string, doc document) (unstructured.Unstructured, *DecodeError) {
+	var u unstructured.Unstructured
+
+	j, err := yaml.YAMLToJSON(doc.Data)
+	if err != nil {
+		line := doc.Line + relativeLine(err) - 1
+		// the parser reports lines relative to the document, rewrite them
+		// to be relative to the source so the message is not misleading.
+		err = errors.New(lineNumber.ReplaceAllString(err.Error(), fmt.Sprintf("line %d", line)))
+		return u, &DecodeError{Position: Position{File: file, Document: doc.Index, Line: line}, Err: err}
+	}
+
+	err = u.UnmarshalJSON(j)
+	if err != nil {
+		return u, &DecodeError{Position: Position{File: file, Document: doc.Index, Line: doc.Line}, Err: err}
+	}
+
+	return u, nil
+}
+
+// relativeLine extracts the line number reported by the yaml parser, defaulting
+// to the first line of the document when none is present.
+func relativeLine(err error) int {
+	m := lineNumber.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 1
+	}
+
+	line, err := strconv.Atoi(m[1])
+	if err != nil || line < 1 {
+		return 1
+	}
+
+	return line
+}
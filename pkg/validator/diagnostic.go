@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+var severities = []Severity{SeverityInfo, SeverityWarning, SeverityError}
+
+// ParseSeverity parses the name of a severity.
+func ParseSeverity(name string) (Severity, error) {
+	for _, s := range severities {
+		if string(s) == name {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity %q, expected one of %v", name, severities)
+}
+
+// AtLeast reports whether s is at least as severe as threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+func (s Severity) rank() int {
+	for i, severity := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// Diagnostic is a single finding against a resource.
+type Diagnostic struct {
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Field      string   `json:"field,omitempty"`
+	Rule       string   `json:"rule"`
+	Severity   Severity `json:"severity"`
+	Message    string   `json:"message"`
+	Position   Position `json:"location"`
+
+	// line and column locate the diagnostic within the value of Field, e.g.
+	// within a script, they are 0 when it applies to the whole field.
+	line   int
+	column int
+}
+
+// Error formats the diagnostic as Kind/Name Message, allowing it to be used as an error.
+func (d Diagnostic) Error() string {
+	if d.Kind == "" {
+		return d.Message
+	}
+	return fmt.Sprintf("%s/%s %s", d.Kind, d.Name, d.Message)
+}
+
+// String formats the diagnostic prefixed with its position and rule.
+func (d Diagnostic) String() string {
+	if d.Position == (Position{}) {
+		return fmt.Sprintf("[%s] %s", d.Rule, d.Error())
+	}
+	return fmt.Sprintf("%s: [%s] %s", d.Position, d.Rule, d.Error())
+}
+
+func newDiagnostic(u unstructured.Unstructured, rule string, severity Severity, message string) Diagnostic {
+	return Diagnostic{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Name:       u.GetName(),
+		Namespace:  u.GetNamespace(),
+		Rule:       rule,
+		Severity:   severity,
+		Message:    message,
+	}
+}
+
+// Resource identifies a resource decoded from a source.
+type Resource struct {
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Position   Position `json:"location"`
+}
+
+func newResource(u unstructured.Unstructured, position Position) Resource {
+	return Resource{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Name:       u.GetName(),
+		Namespace:  u.GetNamespace(),
+		Position:   position,
+	}
+}
+
+// Report is the result of validating a source.
+type Report struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	// Resources are the resources that were validated.
+	Resources []Resource `json:"resources"`
+	// Skipped are the resources that have no validation for their kind.
+	Skipped []Resource `json:"skipped"`
+	// Suppressed are the diagnostics of rules ignored by their resource.
+	Suppressed []Suppression `json:"suppressed"`
+	// Baselined are the diagnostics matching an entry of a Baseline.
+	Baselined []Diagnostic `json:"baselined"`
+}
+
+// Merge appends the contents of other to the report.
+func (r *Report) Merge(other *Report) {
+	r.Diagnostics = append(r.Diagnostics, other.Diagnostics...)
+	r.Resources = append(r.Resources, other.Resources...)
+	r.Skipped = append(r.Skipped, other.Skipped...)
+	r.Suppressed = append(r.Suppressed, other.Suppressed...)
+	r.Baselined = append(r.Baselined, other.Baselined...)
+}
+
+// Errors returns the diagnostics with a severity of error.
+func (r *Report) Errors() []Diagnostic {
+	var errs []Diagnostic
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			errs = append(errs, d)
+		}
+	}
+	return errs
+}
+
+// Failed reports whether any diagnostic is at least as severe as threshold.
+func (r *Report) Failed(threshold Severity) bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
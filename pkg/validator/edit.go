@@ -0,0 +1,374 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var segmentIndex = regexp.MustCompile(`^(.*)\[(\d+)\]$`)
+
+// editor edits the source of a single document. The nodes of the document are
+// only used to locate the lines to change, every other line is left exactly
+// as written so the formatting and comments of the document are preserved.
+type editor struct {
+	lines []string
+	root  *yaml.Node
+}
+
+func newEditor(data []byte) (*editor, error) {
+	e := &editor{lines: strings.Split(string(data), "\n")}
+	if err := e.parse(); err != nil {
+		return nil, err
+	}
+
+	if e.root.Style&yaml.FlowStyle != 0 {
+		return nil, errors.New("only block style YAML documents can be edited, not JSON or flow style")
+	}
+	return e, nil
+}
+
+// edit applies fn to the document. When fn fails the document is restored as
+// it was, so an edit that fails part way through is never left half applied.
+func (e *editor) edit(fn func() error) error {
+	lines := append([]string{}, e.lines...)
+
+	err := fn()
+	if err == nil {
+		return nil
+	}
+
+	e.lines = lines
+	if perr := e.parse(); perr != nil {
+		return perr
+	}
+	return err
+}
+
+// parse indexes the nodes of the document, it is called after every edit so
+// the positions of the nodes match the lines.
+func (e *editor) parse() error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(e.String()), &doc); err != nil {
+		return err
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return errors.New("document is not a mapping")
+	}
+
+	e.root = doc.Content[0]
+	return nil
+}
+
+func (e *editor) String() string {
+	return strings.Join(e.lines, "\n")
+}
+
+// match is the deepest field of a path that exists within the document.
+type match struct {
+	// key is nil for the root of the document and items of a sequence.
+	key   *yaml.Node
+	value *yaml.Node
+	// found is the number of segments of the path that exist.
+	found int
+}
+
+// find looks up the field at path, a segment may index a sequence, e.g. the
+// path spec, params[0], name.
+func (e *editor) find(path []string) match {
+	m := match{value: e.root}
+	for _, segment := range path {
+		name, index := segment, -1
+		if sub := segmentIndex.FindStringSubmatch(segment); sub != nil {
+			name = sub[1]
+			index, _ = strconv.Atoi(sub[2])
+		}
+
+		if m.value.Kind != yaml.MappingNode {
+			return m
+		}
+
+		key, value := mappingValue(m.value, name)
+		if key == nil {
+			return m
+		}
+
+		if index >= 0 {
+			if value.Kind != yaml.SequenceNode || index >= len(value.Content) {
+				return m
+			}
+			key, value = nil, value.Content[index]
+		}
+
+		m = match{key: key, value: value, found: m.found + 1}
+	}
+
+	return m
+}
+
+func mappingValue(mapping *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// set sets the field at path to value, creating any field of the path that
+// does not exist. A []string value is written as a block sequence, any other
+// value as a scalar.
+func (e *editor) set(path []string, value interface{}) error {
+	if err := e.expandFlowMappings(path); err != nil {
+		return err
+	}
+
+	m := e.find(path)
+	if m.found == len(path) {
+		if m.key == nil {
+			return fmt.Errorf("unable to set %s", strings.Join(path, "."))
+		}
+		return e.replace(m.key, m.value, value)
+	}
+
+	missing := path[m.found:]
+
+	// a field without a value, or with an empty flow mapping, e.g.
+	// "securityContext: {}", is replaced by a block mapping.
+	if m.key != nil && (isNull(m.value) || (m.value.Kind == yaml.MappingNode && len(m.value.Content) == 0)) {
+		return e.replaceLines(m.key, m.value, "", render(missing, value, indentOf(m.key)+2))
+	}
+
+	if m.value.Kind != yaml.MappingNode || m.value.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("unable to add %s, %s is not a block mapping", strings.Join(path, "."), strings.Join(path[:m.found], "."))
+	}
+
+	last := m.value.Content[len(m.value.Content)-2]
+	end := e.blockEnd(last, m.value.Content[len(m.value.Content)-1])
+
+	e.splice(end+1, end+1, render(missing, value, indentOf(m.value.Content[0])))
+	return e.parse()
+}
+
+// expandFlowMappings rewrites each flow mapping along path as a block mapping,
+// e.g. "securityContext: {runAsUser: 0}", so its fields can be replaced and
+// added a line at a time.
+func (e *editor) expandFlowMappings(path []string) error {
+	for i := 1; i <= len(path); i++ {
+		m := e.find(path[:i])
+		if m.found < i {
+			return nil
+		}
+		if m.key == nil || m.value.Kind != yaml.MappingNode || m.value.Style&yaml.FlowStyle == 0 || len(m.value.Content) == 0 {
+			continue
+		}
+
+		blockStyle(m.value)
+
+		var b strings.Builder
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err := enc.Encode(m.value); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+
+		indent := strings.Repeat(" ", indentOf(m.key)+2)
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+			lines = append(lines, indent+line)
+		}
+
+		if err := e.replaceLines(m.key, m.value, "", lines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// blockStyle writes the mapping, and the mappings nested within it, in block
+// style, any sequence is left as written.
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle
+	for i := 1; i < len(n.Content); i += 2 {
+		if n.Content[i].Kind == yaml.MappingNode {
+			blockStyle(n.Content[i])
+		}
+	}
+}
+
+// rename replaces the scalar at path with name.
+func (e *editor) rename(path []string, name string) (string, error) {
+	m := e.find(path)
+	if m.found != len(path) || m.key == nil || m.value.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("unable to rename %s", strings.Join(path, "."))
+	}
+
+	old := m.value.Value
+	return old, e.replace(m.key, m.value, name)
+}
+
+// replaceAll replaces every match of re within the document.
+func (e *editor) replaceAll(re *regexp.Regexp, replacement string) error {
+	e.lines = strings.Split(re.ReplaceAllString(e.String(), replacement), "\n")
+	return e.parse()
+}
+
+// replace replaces the value of the field with key.
+func (e *editor) replace(key *yaml.Node, value *yaml.Node, v interface{}) error {
+	items, ok := v.([]string)
+	if !ok {
+		return e.replaceLines(key, value, quote(v), nil)
+	}
+
+	indent := indentOf(key)
+	if value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0 {
+		// keep the indentation of the existing items
+		indent = indentOf(value.Content[0]) - 2
+	}
+
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, strings.Repeat(" ", indent)+"- "+quote(item))
+	}
+	return e.replaceLines(key, value, "", lines)
+}
+
+// replaceLines replaces the value of the field with key by the inline text
+// following the key, or by the lines following it.
+func (e *editor) replaceLines(key *yaml.Node, value *yaml.Node, inline string, lines []string) error {
+	end := e.blockEnd(key, value)
+
+	line := e.lines[key.Line-1]
+	colon := findColon(line, key.Column-1)
+	if colon < 0 {
+		return fmt.Errorf("unable to find the value of %s", key.Value)
+	}
+
+	text := line[:colon+1]
+	if inline != "" {
+		text += " " + inline
+	}
+	if comment := findComment(line, colon+1); comment >= 0 {
+		// keep the spacing between the value and the comment as written.
+		gap := line[colon+1 : comment]
+		text += gap[len(strings.TrimRight(gap, " \t")):] + line[comment:]
+	}
+
+	e.splice(key.Line-1, end+1, append([]string{text}, lines...))
+	return e.parse()
+}
+
+// blockEnd returns the index of the last line of the value of key, lines
+// indented further than the key belong to its value, as do the items of a
+// sequence written at the same indentation as the key.
+func (e *editor) blockEnd(key *yaml.Node, value *yaml.Node) int {
+	indent := indentOf(key)
+	sequence := value.Kind == yaml.SequenceNode
+
+	end := key.Line - 1
+	for i := key.Line; i < len(e.lines); i++ {
+		trimmed := strings.TrimSpace(e.lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		n := len(e.lines[i]) - len(strings.TrimLeft(e.lines[i], " "))
+		if n > indent || (sequence && n == indent && strings.HasPrefix(trimmed, "-")) {
+			end = i
+			continue
+		}
+		break
+	}
+
+	return end
+}
+
+// splice replaces the lines from start up to end with lines.
+func (e *editor) splice(start int, end int, lines []string) {
+	replaced := append([]string{}, e.lines[:start]...)
+	replaced = append(replaced, lines...)
+	e.lines = append(replaced, e.lines[end:]...)
+}
+
+// render creates the lines of a field that does not exist yet.
+func render(path []string, value interface{}, indent int) []string {
+	prefix := strings.Repeat(" ", indent) + quote(path[0]) + ":"
+	if len(path) > 1 {
+		return append([]string{prefix}, render(path[1:], value, indent+2)...)
+	}
+
+	items, ok := value.([]string)
+	if !ok {
+		return []string{prefix + " " + quote(value)}
+	}
+
+	lines := []string{prefix}
+	for _, item := range items {
+		lines = append(lines, strings.Repeat(" ", indent)+"- "+quote(item))
+	}
+	return lines
+}
+
+// quote formats a scalar, quoting it only when required.
+func quote(v interface{}) string {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+func indentOf(n *yaml.Node) int {
+	return n.Column - 1
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+// findColon returns the index of the ':' separating the key starting at start
+// from its value.
+func findColon(line string, start int) int {
+	var quote byte
+	for i := start; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t'):
+			return i
+		}
+	}
+	return -1
+}
+
+// findComment returns the index of the comment following start, or -1 when
+// the line has no comment.
+func findComment(line string, start int) int {
+	var quote byte
+	for i := start; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return i
+		}
+	}
+	return -1
+}
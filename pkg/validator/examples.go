@@ -0,0 +1,1182 @@
+package validator
+
+// Example shows a resource that fails a rule, and the same resource fixed so
+// that it passes.
+type Example struct {
+	Failing string
+	Passing string
+}
+
+const exampleTask = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`
+
+const examplePipeline = `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: build
+`
+
+const exampleComponent = `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: source
+`
+
+// examples are keyed by the ID of the rule they illustrate.
+var examples = map[string]Example{
+	RuleDecode: {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: [build
+`,
+		Passing: examplePipeline,
+	},
+	RuleStructure: {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: nobody
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	RuleSuppressionReason: {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: Build
+  annotations:
+    component-validator.dev/ignore: CV-PIPELINE-002
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: Build
+  annotations:
+    component-validator.dev/ignore: CV-PIPELINE-002
+    component-validator.dev/ignore-reason: Published before names were checked
+`,
+	},
+	RuleUnusedSuppression: {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: build
+  annotations:
+    component-validator.dev/ignore: CV-PIPELINE-002
+    component-validator.dev/ignore-reason: Published before names were checked
+`,
+		Passing: examplePipeline,
+	},
+	"CV-TASK-001": {
+		Failing: `apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-002": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: Build_Image
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-003": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-004": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: false
+      runAsUser: 0
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-005": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: false
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-006": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - NET_RAW
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-007": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: true
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-008": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: Unconfined
+`,
+		Passing: exampleTask,
+	},
+	"CV-TASK-009": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: imageName
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo building $(params.imageName)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: image-name
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo building $(params.image-name)
+`,
+	},
+	"CV-TASK-010": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: imageDigest
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo -n sha256:1234 > $(results.imageDigest.path)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: image-digest
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo -n sha256:1234 > $(results.image-digest.path)
+`,
+	},
+	"CV-TASK-011": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: docker:dind
+    securityContext:
+      privileged: true
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: gcr.io/kaniko-project/executor@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+`,
+	},
+	"CV-TASK-012": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash:5.2
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+`,
+	},
+	"CV-TASK-013": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash:latest
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+`,
+	},
+	"CV-TASK-014": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: docker.io/library/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+`,
+	},
+	"CV-TASK-015": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: image
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: $(params.image)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: image
+    default: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: $(params.image)
+`,
+	},
+	"CV-TASK-016": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: message
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo $(params.mesage)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: message
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo $(params.message)
+`,
+	},
+	"CV-TASK-017": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: message
+  - name: verbose
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo $(params.message)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: message
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo $(params.message)
+`,
+	},
+	"CV-TASK-018": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: flags
+    type: array
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    command: [bash]
+    args: ["-c", "build $(params.flags[*])"]
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: flags
+    type: array
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    command: [build]
+    args: ["$(params.flags[*])"]
+`,
+	},
+	"CV-TASK-019": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: image
+    type: object
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      echo -n '{"url": "ghcr.io/org/app"}' > $(results.image.path)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: image
+    type: object
+    properties:
+      url:
+        type: string
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      echo -n '{"url": "ghcr.io/org/app"}' > $(results.image.path)
+`,
+	},
+	"CV-TASK-020": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: digest
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo -n sha256:1234 > $(results.digset.path)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: digest
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo -n sha256:1234 > $(results.digest.path)
+`,
+	},
+	"CV-TASK-021": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: digest
+  - name: url
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo -n sha256:1234 > $(results.digest.path)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: digest
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: echo -n sha256:1234 > $(results.digest.path)
+`,
+	},
+	"CV-TASK-022": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: release
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: curl -s https://api.github.com/repos/org/app/releases/latest > $(results.release.path)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  results:
+  - name: release
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: curl -s https://api.github.com/repos/org/app/releases/latest | jq -r .tag_name > $(results.release.path)
+`,
+	},
+	"CV-TASK-023": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      #!/usr/bin/env bash
+      if [ -f go.mod ]; then
+        go build ./...
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      #!/usr/bin/env bash
+      if [ -f go.mod ]; then
+        go build ./...
+      fi
+`,
+	},
+	"CV-TASK-024": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      #!/usr/bin/env bash
+      go build ./... | tee build.log
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      #!/usr/bin/env bash
+      set -euo pipefail
+      go build ./... | tee build.log
+`,
+	},
+	"CV-TASK-025": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: message
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      echo $(params.message)
+`,
+		Passing: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  params:
+  - name: message
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    script: |
+      echo "$(params.message)"
+`,
+	},
+	"CV-PIPELINE-001": {
+		Failing: `apiVersion: tekton.dev/v1beta1
+kind: Pipeline
+metadata:
+  name: build
+`,
+		Passing: examplePipeline,
+	},
+	"CV-PIPELINE-002": {
+		Failing: `apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: Build_Image
+`,
+		Passing: examplePipeline,
+	},
+	"CV-COMPONENT-001": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-002": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: Source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-003": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-004": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-component-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-005": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-006": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  pipelineRun:
+    pipelineRef:
+      name: source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-007": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    pipelineRef:
+      name: Source
+`,
+		Passing: exampleComponent,
+	},
+	"CV-COMPONENT-008": {
+		Failing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    params:
+    - name: gitUrl
+      value: $(workload.spec.source.git.url)
+    pipelineRef:
+      name: source
+`,
+		Passing: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: Fetches the source of a workload
+  pipelineRun:
+    params:
+    - name: git-url
+      value: $(workload.spec.source.git.url)
+    pipelineRef:
+      name: source
+`,
+	},
+}
+
+// Example returns a resource that fails the rule and the resource fixed.
+func (r Rule) Example() (Example, bool) {
+	e, ok := examples[r.ID]
+	return e, ok
+}
@@ -0,0 +1,9 @@
+package validator
+
+import "sync"
+
+// ResetSharedValidator discards the shared validator and translator, so the
+// next document validated builds them again.
+func ResetSharedValidator() {
+	shared.once = sync.Once{}
+}
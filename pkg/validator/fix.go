@@ -0,0 +1,174 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stoewer/go-strcase"
+)
+
+// fixer resolves a diagnostic by editing the document it was reported for.
+type fixer func(e *editor, c *Config, d Diagnostic) error
+
+var securityContext = []string{"spec", "stepTemplate", "securityContext"}
+
+// securityContextOf returns the path of the securityContext the diagnostic was
+// reported against, that of the stepTemplate or of a step or sidecar.
+func securityContextOf(d Diagnostic) []string {
+	path := strings.Split(d.Field, ".")
+	for i, segment := range path {
+		if segment == "securityContext" {
+			return path[:i+1]
+		}
+	}
+	return securityContext
+}
+
+// fixers are keyed by the ID of the rule whose diagnostics they resolve.
+var fixers = map[string]fixer{
+	"CV-TASK-006": func(e *editor, _ *Config, d Diagnostic) error {
+		return e.set(fieldOf(securityContextOf(d), "capabilities", "drop"), []string{"ALL"})
+	},
+	"CV-TASK-007": func(e *editor, _ *Config, d Diagnostic) error {
+		return e.set(fieldOf(securityContextOf(d), "allowPrivilegeEscalation"), false)
+	},
+	"CV-TASK-008": func(e *editor, c *Config, d Diagnostic) error {
+		return e.set(fieldOf(securityContextOf(d), "seccompProfile", "type"), c.Param(d.Rule, "types")[0])
+	},
+	"CV-TASK-009": func(e *editor, _ *Config, d Diagnostic) error {
+		return renameReferenced(e, d, "params")
+	},
+	"CV-TASK-010": func(e *editor, _ *Config, d Diagnostic) error {
+		return renameReferenced(e, d, "results")
+	},
+	"CV-TASK-011": func(e *editor, _ *Config, d Diagnostic) error {
+		return e.set(fieldOf(securityContextOf(d), "privileged"), false)
+	},
+	"CV-COMPONENT-005": func(e *editor, c *Config, d Diagnostic) error {
+		return e.set([]string{"metadata", "labels", c.Param(d.Rule, "label")[0]}, c.Param(d.Rule, "values")[0])
+	},
+}
+
+func fieldOf(parent []string, path ...string) []string {
+	return append(append([]string{}, parent...), path...)
+}
+
+// renameReferenced renames a param or result to kebab-case, along with every
+// reference to it within the document, e.g. $(params.name) or
+// $(params["name"]). Only the document declaring it is edited, references
+// from other documents, e.g. a Pipeline passing the param to the Task, keep
+// the old name.
+func renameReferenced(e *editor, d Diagnostic, variable string) error {
+	path := strings.Split(d.Field, ".")
+
+	m := e.find(path)
+	if m.found != len(path) {
+		return fmt.Errorf("unable to rename %s", d.Field)
+	}
+
+	name := strcase.KebabCase(m.value.Value)
+	if name == "" || name == m.value.Value {
+		return fmt.Errorf("unable to rename %s", d.Field)
+	}
+
+	old, err := e.rename(path, name)
+	if err != nil {
+		return err
+	}
+
+	prefix := regexp.QuoteMeta("$(" + variable)
+	quoted := regexp.QuoteMeta(old)
+	references := regexp.MustCompile(prefix + `(\.` + quoted + `([)\[.])|\["` + quoted + `"\]|\['` + quoted + `'\])`)
+
+	return e.replaceAll(references, "$$("+variable+"."+name+"${2}")
+}
+
+// Fixable reports whether diagnostics of the rule can be resolved by Fix.
+func (r Rule) Fixable() bool {
+	_, ok := fixers[r.ID]
+	return ok
+}
+
+// FixReport lists the diagnostics of Fixable rules that Fix resolved, and
+// those it could not.
+type FixReport struct {
+	Fixed   []Diagnostic
+	Unfixed []Unfixed
+}
+
+// Unfixed is a diagnostic of a Fixable rule that could not be resolved.
+type Unfixed struct {
+	Diagnostic
+	// Reason explains why the diagnostic could not be resolved.
+	Reason string
+}
+
+// Fix validates source and edits each document to resolve every diagnostic
+// of a Fixable rule, returning the fixed source and which diagnostics were
+// resolved. A fix that fails leaves its document as it was. Diagnostics of
+// other rules are left for the caller to report by validating the fixed
+// source.
+func (v *Validator) Fix(ctx context.Context, file string, source []byte) ([]byte, FixReport, error) {
+	var fr FixReport
+
+	report, err := v.ValidateBytes(ctx, file, source)
+	if err != nil {
+		return nil, fr, err
+	}
+
+	byDocument := map[int][]Diagnostic{}
+	for _, d := range report.Diagnostics {
+		if _, ok := fixers[d.Rule]; ok {
+			byDocument[d.Position.Document] = append(byDocument[d.Position.Document], d)
+		}
+	}
+
+	if len(byDocument) == 0 {
+		return source, fr, nil
+	}
+
+	var out []string
+
+	lines := strings.SplitAfter(string(source), "\n")
+	next := 0
+	for _, doc := range splitDocuments(source) {
+		diagnostics := byDocument[doc.Index]
+		if len(diagnostics) == 0 {
+			continue
+		}
+
+		e, err := newEditor(doc.Data)
+		if err != nil {
+			for _, d := range diagnostics {
+				fr.Unfixed = append(fr.Unfixed, Unfixed{Diagnostic: d, Reason: err.Error()})
+			}
+			continue
+		}
+
+		for _, d := range diagnostics {
+			err := e.edit(func() error {
+				return fixers[d.Rule](e, v.options.Config, d)
+			})
+			if err != nil {
+				fr.Unfixed = append(fr.Unfixed, Unfixed{Diagnostic: d, Reason: err.Error()})
+				continue
+			}
+			fr.Fixed = append(fr.Fixed, d)
+		}
+
+		// copy every line before the document as is, then replace the lines of
+		// the document with its fixed source.
+		start := doc.Line - 1
+		out = append(out, lines[next:start]...)
+		out = append(out, e.String())
+		next = start + len(strings.SplitAfter(string(doc.Data), "\n")) - 1
+		if !strings.HasSuffix(string(doc.Data), "\n") {
+			next++
+		}
+	}
+	out = append(out, lines[next:]...)
+
+	return []byte(strings.Join(out, "")), fr, nil
+}
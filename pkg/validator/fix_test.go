@@ -0,0 +1,342 @@
+package validator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFix(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+		config   *validator.Config
+		rules    []string
+	}{
+		{
+			name: "security context",
+			source: `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task # the name
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: true # must be false
+      capabilities:
+        drop:
+        - NET_RAW
+        - CHOWN
+      runAsNonRoot: true
+      runAsUser: 1001
+`,
+			expected: `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task # the name
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false # must be false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+			rules: []string{"CV-TASK-007", "CV-TASK-006", "CV-TASK-008"},
+		},
+		{
+			name: "empty capabilities",
+			source: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      runAsNonRoot: true
+      runAsUser: 1001
+      capabilities: # drop everything
+      seccompProfile: {}
+`,
+			expected: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      runAsNonRoot: true
+      runAsUser: 1001
+      capabilities: # drop everything
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
+`,
+			rules: []string{"CV-TASK-006", "CV-TASK-008"},
+		},
+		{
+			name: "step security context",
+			source: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: docker.io/library/docker@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    securityContext:
+      privileged: true    # needs docker
+      allowPrivilegeEscalation: true
+`,
+			expected: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: docker.io/library/docker@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    securityContext:
+      privileged: false    # needs docker
+      allowPrivilegeEscalation: false
+`,
+			rules: []string{"CV-TASK-007", "CV-TASK-011"},
+		},
+		{
+			name: "flow security context",
+			source: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext: {runAsNonRoot: true, runAsUser: 1001, capabilities: {add: [NET_RAW]}}  # from upstream
+`,
+			expected: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:  # from upstream
+      runAsNonRoot: true
+      runAsUser: 1001
+      capabilities:
+        add: [NET_RAW]
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
+`,
+			rules: []string{"CV-TASK-006", "CV-TASK-008"},
+		},
+		{
+			name: "params and results",
+			source: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  params:
+  - name: Source_URL # where from
+  results:
+  - name: imageRef
+  steps:
+  - name: build
+    args: ["$(params['Source_URL'])"]
+    script: |
+      echo $(params.Source_URL) $(params["Source_URL"])
+      echo $(params.Source_URL_Other)
+      echo done > $(results.imageRef.path)
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: [ALL]
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+			expected: `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  params:
+  - name: source-url # where from
+  results:
+  - name: image-ref
+  steps:
+  - name: build
+    args: ["$(params.source-url)"]
+    script: |
+      echo $(params.source-url) $(params.source-url)
+      echo $(params.Source_URL_Other)
+      echo done > $(results.image-ref.path)
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: [ALL]
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+`,
+			// the undeclared param is left alone, though its name has the
+			// renamed param as a prefix
+			config: &validator.Config{Rules: map[string]validator.RuleConfig{
+				"CV-TASK-016": {Enabled: &disabled},
+			}},
+			rules: []string{"CV-TASK-009", "CV-TASK-010"},
+		},
+		{
+			name: "catalog label",
+			source: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    app: source
+spec:
+  description: builds source
+  pipelineRun:
+    pipelineRef:
+      name: source
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+`,
+			expected: `apiVersion: supply-chain.apps.tanzu.vmware.com/v1alpha1
+kind: Component
+metadata:
+  name: source-1.0.0
+  labels:
+    app: source
+    supply-chain.apps.tanzu.vmware.com/catalog: tanzu
+spec:
+  description: builds source
+  pipelineRun:
+    pipelineRef:
+      name: source
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unchanged
+`,
+			rules: []string{"CV-COMPONENT-005"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validator.New(validator.Options{Config: tc.config})
+
+			fixed, fr, err := v.Fix(context.Background(), "carvel.yaml", []byte(tc.source))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(fixed))
+
+			var rules []string
+			for _, d := range fr.Fixed {
+				rules = append(rules, d.Rule)
+			}
+			assert.Equal(t, tc.rules, rules)
+			assert.Empty(t, fr.Unfixed)
+
+			report, err := v.ValidateBytes(context.Background(), "carvel.yaml", fixed)
+			require.NoError(t, err)
+			assert.Empty(t, report.Diagnostics)
+		})
+	}
+}
+
+func TestFixNothing(t *testing.T) {
+	source := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: my-config\n")
+
+	fixed, fr, err := validator.New(validator.Options{}).Fix(context.Background(), "", source)
+	require.NoError(t, err)
+	assert.Equal(t, source, fixed)
+	assert.Empty(t, fr.Fixed)
+	assert.Empty(t, fr.Unfixed)
+}
+
+func TestFixFailed(t *testing.T) {
+	source := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+  steps: [{name: build, image: "docker.io/library/docker@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4", securityContext: {privileged: true}}]
+---
+{"apiVersion": "supply-chain.apps.tanzu.vmware.com/v1alpha1", "kind": "Component", "metadata": {"name": "source-1.0.0"}, "spec": {"description": "builds source", "pipelineRun": {"pipelineRef": {"name": "source"}}}}
+`
+
+	fixed, fr, err := validator.New(validator.Options{}).Fix(context.Background(), "carvel.yaml", []byte(source))
+	require.NoError(t, err)
+
+	// the privileged step is within a flow sequence, which cannot be edited
+	// a line at a time, so it is left exactly as written.
+	assert.Equal(t, strings.Replace(source, "      runAsUser: 1001\n", "      runAsUser: 1001\n      seccompProfile:\n        type: RuntimeDefault\n", 1), string(fixed))
+
+	var rules []string
+	for _, d := range fr.Fixed {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{"CV-TASK-008"}, rules)
+
+	var unfixed []string
+	for _, u := range fr.Unfixed {
+		unfixed = append(unfixed, u.Rule+": "+u.Reason)
+	}
+	assert.Equal(t, []string{
+		"CV-TASK-011: yaml: line 15: did not find expected ',' or '}'",
+		"CV-COMPONENT-005: only block style YAML documents can be edited, not JSON or flow style",
+	}, unfixed)
+}
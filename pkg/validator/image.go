@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	ruleImageDigest     = "CV-TASK-012"
+	ruleImageLatest     = "CV-TASK-013"
+	ruleImageRegistry   = "CV-TASK-014"
+	ruleImageUnresolved = "CV-TASK-015"
+
+	defaultRegistry = "docker.io"
+)
+
+var (
+	// imageDigest matches the digest an image is pinned by.
+	imageDigest = regexp.MustCompile(`@sha256:[0-9a-f]{64}$`)
+	// variable matches any variable substituted by Tekton.
+	variable = regexp.MustCompile(`\$\([^)]*\)`)
+)
+
+// image is a parsed image reference, e.g. ghcr.io/org/app:1.0@sha256:...
+type image struct {
+	// repository includes the registry, e.g. docker.io/library/alpine.
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseImage parses a reference, images without a registry are on Docker Hub.
+func parseImage(ref string) image {
+	var i image
+	if at := strings.Index(ref, "@"); at >= 0 {
+		ref, i.digest = ref[:at], ref[at+1:]
+	}
+	if colon := strings.LastIndex(ref, ":"); colon > strings.LastIndex(ref, "/") {
+		ref, i.tag = ref[:colon], ref[colon+1:]
+	}
+
+	first, _, found := strings.Cut(ref, "/")
+	switch {
+	case !found:
+		ref = defaultRegistry + "/library/" + ref
+	case !strings.ContainsAny(first, ".:") && first != "localhost":
+		ref = defaultRegistry + "/" + ref
+	}
+	i.repository = ref
+
+	return i
+}
+
+// allowed reports whether the repository is within one of the registries, a
+// registry may include the path of a repository, e.g. ghcr.io/org.
+func (i image) allowed(registries []string) bool {
+	for _, r := range registries {
+		r = strings.TrimSuffix(r, "/")
+		if i.repository == r || strings.HasPrefix(i.repository, r+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateImages checks the image of the stepTemplate and of each step and
+// sidecar. Params referenced by an image are replaced by their defaults, an
+// image that still references a variable cannot be checked and is reported as
+// such.
+func validateImages(ctx context.Context, u unstructured.Unstructured) []Diagnostic {
+	c := configFrom(ctx)
+	defaults := paramDefaults(u)
+
+	var diagnostics []Diagnostic
+	check := func(field string, owner string, ref string) {
+		resolved := paramReferences.ReplaceAllStringFunc(ref, func(m string) string {
+			r := parseParamReference(m)
+			if value, ok := defaults[r.name]; ok && r.suffix == "" {
+				return value
+			}
+			return m
+		})
+
+		report := func(rule string, severity Severity, format string, args ...interface{}) {
+			message := fmt.Sprintf(format, args...)
+			if resolved != ref {
+				message += fmt.Sprintf(", resolved from %s", ref)
+			}
+
+			d := newDiagnostic(u, rule, severity, owner+message)
+			d.Field = field
+			diagnostics = append(diagnostics, d)
+		}
+
+		if variable.MatchString(resolved) {
+			report(ruleImageUnresolved, SeverityInfo, "image %s cannot be checked as it references a variable without a default", resolved)
+			return
+		}
+
+		i := parseImage(resolved)
+		if !imageDigest.MatchString(resolved) {
+			report(ruleImageDigest, SeverityError, "image %s is not pinned by a sha256 digest", resolved)
+		}
+		if i.tag == "latest" || (i.tag == "" && i.digest == "") {
+			report(ruleImageLatest, SeverityError, "image %s uses the latest tag", resolved)
+		}
+		if registries := c.Param(ruleImageRegistry, "registries"); len(registries) > 0 && !i.allowed(registries) {
+			report(ruleImageRegistry, SeverityError, "image %s is not from one of the allowed registries %v", resolved, registries)
+		}
+	}
+
+	forEachContainer(u, func(path string, owner string, container map[string]interface{}) {
+		if ref, _ := container["image"].(string); ref != "" {
+			check(path+"image", owner, ref)
+		}
+	})
+
+	return diagnostics
+}
+
+// paramDefaults returns the string default of each param of the Task.
+func paramDefaults(u unstructured.Unstructured) map[string]string {
+	defaults := map[string]string{}
+
+	params, _, _ := unstructured.NestedSlice(u.Object, "spec", "params")
+	for _, p := range params {
+		param, _ := p.(map[string]interface{})
+		name, _ := param["name"].(string)
+		if value, ok := param["default"].(string); ok && name != "" {
+			defaults[name] = value
+		}
+	}
+
+	return defaults
+}
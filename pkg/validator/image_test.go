@@ -0,0 +1,76 @@
+package validator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const digest = "sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4"
+
+func TestValidateImages(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  params:
+  - name: image
+    default: ghcr.io/org/app:1.0
+  - name: tag
+  stepTemplate:
+    image: alpine
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: pinned
+    image: cgr.dev/chainguard/bash@` + digest + `
+  - name: latest
+    image: cgr.dev/chainguard/bash:latest@` + digest + `
+  - name: default
+    image: $(params["image"])
+  - name: unresolved
+    image: cgr.dev/chainguard/bash:$(params.tag)
+  sidecars:
+  - name: registry
+    image: localhost:5000/registry:2@` + digest + `
+`
+
+	config := &validator.Config{Rules: map[string]validator.RuleConfig{
+		"CV-TASK-014": {Params: map[string]validator.Values{"registries": {"cgr.dev/chainguard", "localhost:5000"}}},
+	}}
+
+	report, err := validator.New(validator.Options{Config: config}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		if strings.Contains(d.Field, "image") {
+			diagnostics = append(diagnostics, strings.TrimPrefix(d.String(), "carvel.yaml:"))
+		}
+	}
+
+	assert.Equal(t, []string{
+		"11:5: [CV-TASK-012] Task/my-task stepTemplate: image alpine is not pinned by a sha256 digest",
+		"11:5: [CV-TASK-013] Task/my-task stepTemplate: image alpine uses the latest tag",
+		"11:5: [CV-TASK-014] Task/my-task stepTemplate: image alpine is not from one of the allowed registries [cgr.dev/chainguard localhost:5000]",
+		"25:5: [CV-TASK-013] Task/my-task step latest: image cgr.dev/chainguard/bash:latest@" + digest + " uses the latest tag",
+		"27:5: [CV-TASK-012] Task/my-task step default: image ghcr.io/org/app:1.0 is not pinned by a sha256 digest, resolved from $(params[\"image\"])",
+		"27:5: [CV-TASK-014] Task/my-task step default: image ghcr.io/org/app:1.0 is not from one of the allowed registries [cgr.dev/chainguard localhost:5000], resolved from $(params[\"image\"])",
+		"29:5: [CV-TASK-015] Task/my-task step unresolved: image cgr.dev/chainguard/bash:$(params.tag) cannot be checked as it references a variable without a default",
+	}, diagnostics)
+
+	assert.Equal(t, validator.SeverityInfo, report.Diagnostics[len(report.Diagnostics)-1].Severity)
+}
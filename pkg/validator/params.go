@@ -0,0 +1,235 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	ruleParamUndeclared = "CV-TASK-016"
+	ruleParamUnused     = "CV-TASK-017"
+	ruleParamExpansion  = "CV-TASK-018"
+
+	paramTypeString = "string"
+	paramTypeArray  = "array"
+	paramTypeObject = "object"
+)
+
+// paramReferences matches a reference to a param, e.g. $(params.name),
+// $(params["name"]), $(params.name[*]), $(params.name[0]) or
+// $(params.name.key).
+var paramReferences = regexp.MustCompile(`\$\(params(?:\.([\w-]+)|\["([^"]+)"\]|\['([^']+)'\])(\[\*\]|\[\d+\]|\.[\w-]+)?\)`)
+
+// paramReference is a single reference to a param.
+type paramReference struct {
+	// text is the reference as written, e.g. $(params.name[*]).
+	text string
+	name string
+	// suffix follows the name, it is [*] to expand an array or object, [0] to
+	// index an array, .key for a key of an object, or empty.
+	suffix string
+}
+
+func parseParamReference(text string) paramReference {
+	m := paramReferences.FindStringSubmatch(text)
+	r := paramReference{text: m[0], suffix: m[4]}
+	for _, name := range m[1:4] {
+		if name != "" {
+			r.name = name
+		}
+	}
+	return r
+}
+
+func findParamReferences(s string) []paramReference {
+	var references []paramReference
+	for _, text := range paramReferences.FindAllString(s, -1) {
+		references = append(references, parseParamReference(text))
+	}
+	return references
+}
+
+// declaredParam is a param in the spec of a Task.
+type declaredParam struct {
+	name string
+	// kind is string, array or object, inferred from the default when the
+	// type is not specified as Tekton does.
+	kind  string
+	index int
+}
+
+func declaredParams(u unstructured.Unstructured) []declaredParam {
+	var declared []declaredParam
+
+	params, _, _ := unstructured.NestedSlice(u.Object, "spec", "params")
+	for i, p := range params {
+		param, _ := p.(map[string]interface{})
+		name, _ := param["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		kind, _ := param["type"].(string)
+		if kind == "" {
+			switch param["default"].(type) {
+			case []interface{}:
+				kind = paramTypeArray
+			case map[string]interface{}:
+				kind = paramTypeObject
+			default:
+				kind = paramTypeString
+			}
+		}
+
+		declared = append(declared, declaredParam{name: name, kind: kind, index: i})
+	}
+
+	return declared
+}
+
+// validateParams checks each param referenced by the stepTemplate, steps and
+// sidecars is declared and referenced as its type allows, and that every
+// declared param is referenced somewhere within the spec.
+func validateParams(u unstructured.Unstructured) []Diagnostic {
+	declared := map[string]declaredParam{}
+	for _, p := range declaredParams(u) {
+		declared[p.name] = p
+	}
+
+	var diagnostics []Diagnostic
+	report := func(rule string, severity Severity, field string, message string) {
+		d := newDiagnostic(u, rule, severity, message)
+		d.Field = field
+		diagnostics = append(diagnostics, d)
+	}
+
+	// element is set for the items of args and command, the only values an
+	// array may be expanded into.
+	check := func(field string, owner string, value string, element bool) {
+		for _, r := range findParamReferences(value) {
+			p, ok := declared[r.name]
+			if !ok {
+				report(ruleParamUndeclared, SeverityError, field, fmt.Sprintf("%s%s references the undeclared param %s", owner, r.text, r.name))
+				continue
+			}
+
+			if message := r.misuse(p, element && value == r.text); message != "" {
+				report(ruleParamExpansion, SeverityError, field, owner+message)
+			}
+		}
+	}
+
+	forEachContainerString(u, check)
+
+	used := map[string]bool{}
+	spec, _, _ := unstructured.NestedMap(u.Object, "spec")
+	delete(spec, "params")
+	walkStrings(spec, func(s string) {
+		for _, r := range findParamReferences(s) {
+			used[r.name] = true
+		}
+	})
+
+	for _, p := range declaredParams(u) {
+		if !used[p.name] {
+			report(ruleParamUnused, SeverityWarning, fmt.Sprintf("spec.params[%d].name", p.index), fmt.Sprintf("param %s is declared but never referenced", p.name))
+		}
+	}
+
+	return diagnostics
+}
+
+// misuse describes how the reference misuses the type of the param, or is
+// empty when the reference is valid. An array or object may only be expanded
+// with [*] when the reference is the whole of an item of args or command.
+func (r paramReference) misuse(p declaredParam, element bool) string {
+	switch {
+	case r.suffix == "[*]" && p.kind == paramTypeString:
+		return fmt.Sprintf("%s expands the string param %s, only array and object params can be expanded", r.text, p.name)
+	case r.suffix == "[*]" && !element:
+		return fmt.Sprintf("%s expands the %s param %s, it can only be expanded as an entire item of args or command", r.text, p.kind, p.name)
+	case r.suffix == "" && p.kind != paramTypeString:
+		return fmt.Sprintf("%s references the %s param %s as a string, expand it with %s or reference an item", r.text, p.kind, p.name, strings.TrimSuffix(r.text, ")")+"[*])")
+	case len(r.suffix) > 1 && r.suffix[0] == '[' && r.suffix != "[*]" && p.kind != paramTypeArray:
+		return fmt.Sprintf("%s indexes the %s param %s, only array params can be indexed", r.text, p.kind, p.name)
+	case len(r.suffix) > 1 && r.suffix[0] == '.' && p.kind != paramTypeObject:
+		return fmt.Sprintf("%s references a key of the %s param %s, only object params have keys", r.text, p.kind, p.name)
+	}
+	return ""
+}
+
+// forEachContainer calls fn with the stepTemplate, and each step and sidecar,
+// of the Task. The path is the prefix of the fields of the container, e.g.
+// spec.steps[0]. and owner names it within messages, e.g. "step build: ".
+func forEachContainer(u unstructured.Unstructured, fn func(path string, owner string, container map[string]interface{})) {
+	if template, ok, _ := unstructured.NestedMap(u.Object, "spec", "stepTemplate"); ok {
+		fn(stepTemplatePath, "stepTemplate: ", template)
+	}
+
+	for _, kind := range []struct {
+		field string
+		name  string
+	}{
+		{field: "steps", name: "step"},
+		{field: "sidecars", name: "sidecar"},
+	} {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", kind.field)
+		for i, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			owner := fmt.Sprintf("%s %d: ", kind.name, i)
+			if name, _ := container["name"].(string); name != "" {
+				owner = fmt.Sprintf("%s %s: ", kind.name, name)
+			}
+			fn(fmt.Sprintf("spec.%s[%d].", kind.field, i), owner, container)
+		}
+	}
+}
+
+// forEachContainerString calls fn with each string of a container that Tekton
+// substitutes variables within, its image, command, args, workingDir, the
+// value of each env and its script. element is set for the items of args and
+// command.
+func forEachContainerString(u unstructured.Unstructured, fn func(field string, owner string, value string, element bool)) {
+	forEachContainer(u, func(path string, owner string, container map[string]interface{}) {
+		for _, key := range []string{"image", "command", "args", "workingDir", "env", "script"} {
+			switch value := container[key].(type) {
+			case string:
+				fn(path+key, owner, value, false)
+			case []interface{}:
+				for i, item := range value {
+					field := fmt.Sprintf("%s%s[%d]", path, key, i)
+					if s, ok := item.(string); ok {
+						fn(field, owner, s, key != "env")
+					} else if env, ok := item.(map[string]interface{}); ok {
+						if s, ok := env["value"].(string); ok {
+							fn(field+".value", owner, s, false)
+						}
+					}
+				}
+			}
+		}
+	})
+}
+
+// walkStrings calls fn with every string within value.
+func walkStrings(value interface{}, fn func(s string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case []interface{}:
+		for _, item := range v {
+			walkStrings(item, fn)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			walkStrings(item, fn)
+		}
+	}
+}
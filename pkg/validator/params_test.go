@@ -0,0 +1,74 @@
+package validator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateParams(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  params:
+  - name: flags
+    type: array
+  - name: config
+    default:
+      path: /workspace
+  - name: message
+  - name: unused
+  stepTemplate:
+    env:
+    - name: MESSAGE
+      value: $(params.message)
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@` + digest + `
+    command: ["build", "$(params.flags[*])"]
+    args: ["--config", "$(params.config.path)", "$(params.flags[0])"]
+    workingDir: $(params['config'].path)
+    script: echo $(params.mesage)
+  - name: misuse
+    image: cgr.dev/chainguard/bash@` + digest + `
+    args: ["--flags=$(params.flags[*])", "$(params.message[*])", "$(params.flags)"]
+    env:
+    - name: PATH
+      value: $(params.config[0])
+    script: echo $(params.message.key)
+`
+
+	report, err := validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, strings.TrimPrefix(d.String(), "carvel.yaml:"))
+	}
+
+	assert.Equal(t, []string{
+		"33:5: [CV-TASK-016] Task/my-task step build: $(params.mesage) references the undeclared param mesage",
+		"36:12: [CV-TASK-018] Task/my-task step misuse: $(params.flags[*]) expands the array param flags, it can only be expanded as an entire item of args or command",
+		"36:42: [CV-TASK-018] Task/my-task step misuse: $(params.message[*]) expands the string param message, only array and object params can be expanded",
+		"36:66: [CV-TASK-018] Task/my-task step misuse: $(params.flags) references the array param flags as a string, expand it with $(params.flags[*]) or reference an item",
+		"39:7: [CV-TASK-018] Task/my-task step misuse: $(params.config[0]) indexes the object param config, only array params can be indexed",
+		"40:5: [CV-TASK-018] Task/my-task step misuse: $(params.message.key) references a key of the string param message, only object params have keys",
+		"13:5: [CV-TASK-017] Task/my-task param unused is declared but never referenced",
+	}, diagnostics)
+}
@@ -0,0 +1,30 @@
+package validator
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ValidatePipeline validates a Tekton Pipeline, it is registered for every
+// version of tekton.dev/Pipeline by DefaultRegistry.
+func ValidatePipeline(ctx context.Context, u *unstructured.Unstructured) []Diagnostic {
+	validate, translator, err := getValidator()
+	if err != nil {
+		return []Diagnostic{newDiagnostic(*u, RuleStructure, SeverityError, err.Error())}
+	}
+
+	fields := &struct {
+		APIVersion string `json:"apiVersion" validate:"required,tekton-api-version=CV-PIPELINE-001"`
+		Kind       string `json:"kind" validate:"required,eq=Pipeline"`
+		Metadata   struct {
+			Name string `json:"name" validate:"required,kebab-case"`
+		} `json:"metadata"`
+	}{}
+
+	if diagnostics := convert(*u, &fields); diagnostics != nil {
+		return diagnostics
+	}
+
+	return translate(ctx, *u, fields, validate.StructCtx(ctx, fields), translator)
+}
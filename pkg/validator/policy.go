@@ -0,0 +1,231 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// policyRuleName matches the rules of a policy that report violations, as
+// with conftest a rule may be suffixed, e.g. deny_latest_tag.
+var policyRuleName = regexp.MustCompile(`^(deny|warn)(_[a-zA-Z0-9]+)*$`)
+
+// Policy holds Rego policies evaluated against every resource. Each deny rule
+// reports errors and each warn rule reports warnings, e.g.
+//
+//	package main
+//
+//	deny[msg] {
+//	  input.kind == "Task"
+//	  not input.metadata.labels.team
+//	  msg := "Tasks must have a team label"
+//	}
+//
+// The resource being validated is the input, every resource being validated
+// is available for cross-resource checks as data.inventory, which follows the
+// layout used by Gatekeeper, i.e.
+// data.inventory.namespace[namespace][apiVersion][kind][name] and
+// data.inventory.cluster[apiVersion][kind][name] for resources without a
+// namespace.
+//
+// A message may be a string, or an object with a msg and optionally the rule
+// and field to report it against. Messages are reported against a rule named
+// after the package and rule, e.g. main.deny, unless they specify a rule.
+type Policy struct {
+	compiler *ast.Compiler
+	rules    []policyRule
+}
+
+type policyRule struct {
+	id       string
+	query    string
+	severity Severity
+}
+
+// LoadPolicy loads and compiles every .rego file within paths, which may be
+// files or directories.
+func LoadPolicy(paths ...string) (*Policy, error) {
+	result, err := loader.AllRegos(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := result.ParsedModules()
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no policies found in %v", paths)
+	}
+
+	compiler := ast.NewCompiler()
+	if compiler.Compile(modules); compiler.Failed() {
+		return nil, fmt.Errorf("invalid policy: %w", compiler.Errors)
+	}
+
+	p := &Policy{compiler: compiler}
+	seen := map[string]bool{}
+	for _, module := range modules {
+		for _, rule := range module.Rules {
+			name := rule.Head.Name.String()
+			if !policyRuleName.MatchString(name) {
+				continue
+			}
+
+			ref := module.Package.Path.Append(ast.StringTerm(name))
+			if seen[ref.String()] {
+				continue
+			}
+			seen[ref.String()] = true
+
+			severity := SeverityError
+			if strings.HasPrefix(name, "warn") {
+				severity = SeverityWarning
+			}
+
+			p.rules = append(p.rules, policyRule{
+				id:       strings.TrimPrefix(module.Package.Path.String(), "data.") + "." + name,
+				query:    ref.String(),
+				severity: severity,
+			})
+		}
+	}
+
+	sort.Slice(p.rules, func(i, j int) bool {
+		return p.rules[i].id < p.rules[j].id
+	})
+
+	return p, nil
+}
+
+// Rules returns the ID of each deny and warn rule, a message may still be
+// reported against another rule.
+func (p *Policy) Rules() []string {
+	ids := make([]string, 0, len(p.rules))
+	for _, r := range p.rules {
+		ids = append(ids, r.id)
+	}
+	return ids
+}
+
+// preparedPolicy is a Policy ready to be evaluated against the resources of an
+// inventory, it is a ResourceValidator.
+type preparedPolicy struct {
+	rules   []policyRule
+	queries []rego.PreparedEvalQuery
+}
+
+// prepare prepares each rule of the policy for evaluation with resources as
+// the inventory.
+func (p *Policy) prepare(ctx context.Context, resources []unstructured.Unstructured) (*preparedPolicy, error) {
+	store := inmem.NewFromObject(map[string]interface{}{"inventory": inventory(resources)})
+
+	prepared := &preparedPolicy{rules: p.rules}
+	for _, r := range p.rules {
+		q, err := rego.New(
+			rego.Query(r.query),
+			rego.Compiler(p.compiler),
+			rego.Store(store),
+		).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to prepare %s: %w", r.id, err)
+		}
+		prepared.queries = append(prepared.queries, q)
+	}
+
+	return prepared, nil
+}
+
+// inventory indexes resources as Gatekeeper does.
+func inventory(resources []unstructured.Unstructured) map[string]interface{} {
+	cluster := map[string]interface{}{}
+	namespaced := map[string]interface{}{}
+
+	for _, u := range resources {
+		parent := cluster
+		if ns := u.GetNamespace(); ns != "" {
+			parent = child(namespaced, ns)
+		}
+		child(child(parent, u.GetAPIVersion()), u.GetKind())[u.GetName()] = u.Object
+	}
+
+	return map[string]interface{}{"cluster": cluster, "namespace": namespaced}
+}
+
+func child(m map[string]interface{}, key string) map[string]interface{} {
+	c, ok := m[key].(map[string]interface{})
+	if !ok {
+		c = map[string]interface{}{}
+		m[key] = c
+	}
+	return c
+}
+
+// Validate evaluates every deny and warn rule with the resource as input.
+func (p *preparedPolicy) Validate(ctx context.Context, u *unstructured.Unstructured) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, q := range p.queries {
+		r := p.rules[i]
+
+		rs, err := q.Eval(ctx, rego.EvalInput(u.Object))
+		if err != nil && ctx.Err() != nil {
+			// validation was cancelled, the resource is not reported.
+			return nil
+		}
+		if err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(*u, r.id, r.severity, fmt.Sprintf("unable to evaluate %s: %s", r.id, err)))
+			continue
+		}
+
+		for _, result := range rs {
+			for _, e := range result.Expressions {
+				messages, ok := e.Value.([]interface{})
+				if !ok {
+					messages = []interface{}{e.Value}
+				}
+				for _, m := range messages {
+					diagnostics = append(diagnostics, r.diagnostic(*u, m))
+				}
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// diagnostic reports a message of the rule, which is either a string or an
+// object with a msg and optionally a rule and field.
+func (r policyRule) diagnostic(u unstructured.Unstructured, message interface{}) Diagnostic {
+	object, ok := message.(map[string]interface{})
+	if !ok {
+		return newDiagnostic(u, r.id, r.severity, fmt.Sprint(message))
+	}
+
+	d := newDiagnostic(u, r.id, r.severity, fmt.Sprint(object["msg"]))
+	if rule, ok := object["rule"].(string); ok && rule != "" {
+		d.Rule = rule
+	}
+	if field, ok := object["field"].(string); ok {
+		d.Field = field
+	}
+	return d
+}
+
+type policyKey struct{}
+
+// withPolicy returns a context that carries the prepared policy to validate.
+func withPolicy(ctx context.Context, p *preparedPolicy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// policyFrom returns the prepared policy carried by ctx, or nil when there is
+// no policy.
+func policyFrom(ctx context.Context) *preparedPolicy {
+	p, _ := ctx.Value(policyKey{}).(*preparedPolicy)
+	return p
+}
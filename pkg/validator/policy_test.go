@@ -0,0 +1,148 @@
+package validator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const policy = `package main
+
+deny[msg] {
+  input.kind == "Task"
+  not input.metadata.labels.team
+  msg := sprintf("%s must have a team label", [input.metadata.name])
+}
+
+warn_description[msg] {
+  input.kind == "Pipeline"
+  not input.spec.description
+  msg := {"msg": "Pipelines should have a description", "field": "spec", "rule": "ORG-PIPELINE-001"}
+}
+
+# every task referenced by a pipeline must be defined alongside it
+deny_missing_task[msg] {
+  input.kind == "Pipeline"
+  ref := input.spec.tasks[_].taskRef.name
+  not data.inventory.cluster["tekton.dev/v1"].Task[ref]
+  msg := sprintf("task %s is not defined", [ref])
+}
+`
+
+func writePolicy(t *testing.T, policy string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "policy.rego"), []byte(policy), 0o600))
+	return dir
+}
+
+func TestPolicy(t *testing.T) {
+	doc := `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+spec:
+  tasks:
+  - name: first
+    taskRef:
+      name: my-task
+  - name: second
+    taskRef:
+      name: other-task
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+`
+
+	p, err := validator.LoadPolicy(writePolicy(t, policy))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.deny", "main.deny_missing_task", "main.warn_description"}, p.Rules())
+
+	report, err := validator.New(validator.Options{Policy: p}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		if !strings.HasPrefix(d.Rule, "CV-") {
+			diagnostics = append(diagnostics, d.String())
+		}
+		if d.Rule == "ORG-PIPELINE-001" {
+			assert.Equal(t, validator.SeverityWarning, d.Severity)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"carvel.yaml:2:1: [main.deny] Task/my-task my-task must have a team label",
+		"carvel.yaml:7:1: [main.deny_missing_task] Pipeline/my-pipeline task other-task is not defined",
+		"carvel.yaml:11:1: [ORG-PIPELINE-001] Pipeline/my-pipeline Pipelines should have a description",
+	}, diagnostics)
+	assert.Len(t, report.Resources, 3)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestPolicyValidateObject(t *testing.T) {
+	p, err := validator.LoadPolicy(writePolicy(t, policy))
+	require.NoError(t, err)
+
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Task",
+		"metadata":   map[string]interface{}{"name": "my-task"},
+	}}
+
+	report, err := validator.New(validator.Options{Policy: p}).ValidateObject(context.Background(), u)
+	require.NoError(t, err)
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, "my-task must have a team label", report.Diagnostics[0].Message)
+}
+
+func TestPolicyCancelled(t *testing.T) {
+	p, err := validator.LoadPolicy(writePolicy(t, `package main
+
+# never matches, but is still evaluating when cancelled
+deny[msg] {
+  count([x | x := numbers.range(1, 1000000)[_]; x < 0]) > 0
+  msg := "unreachable"
+}
+`))
+	require.NoError(t, err)
+
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "Pipeline",
+		"metadata":   map[string]interface{}{"name": "my-pipeline"},
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := validator.New(validator.Options{Policy: p}).ValidateObject(ctx, u)
+	require.NoError(t, err)
+	assert.Empty(t, report.Diagnostics)
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	_, err := validator.LoadPolicy(writePolicy(t, "package main\n\ndeny[msg] {\n  msg := unknown\n}\n"))
+	assert.ErrorContains(t, err, "invalid policy")
+
+	_, err = validator.LoadPolicy(t.TempDir())
+	assert.ErrorContains(t, err, "no policies found")
+}
@@ -0,0 +1,287 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position identifies a location within a source file.
+type Position struct {
+	File string `json:"file,omitempty"`
+	// Document is the 1-based index of the document within the file.
+	Document int `json:"document"`
+	// Line is the 1-based line within the file.
+	Line int `json:"line"`
+	// Column is the 1-based column within the line, 0 when unknown.
+	Column int `json:"column,omitempty"`
+}
+
+// String formats the position as file:line:column so it can be linked to by
+// editors and CI systems.
+func (p Position) String() string {
+	var parts []string
+	if p.File != "" {
+		parts = append(parts, p.File)
+	}
+
+	if p.Line > 0 {
+		parts = append(parts, strconv.Itoa(p.Line))
+	}
+	if p.Line > 0 && p.Column > 0 {
+		parts = append(parts, strconv.Itoa(p.Column))
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// Positions records the position of each field within a decoded document,
+// keyed by its path e.g. spec.params[0].name.
+type Positions struct {
+	document Position
+	fields   map[string]Position
+	// values locate the lines within scalar values, e.g. of a script.
+	values map[string]scalar
+}
+
+// scalar is where a scalar value starts within the source.
+type scalar struct {
+	start Position
+	// multiline is set when every line of the value is a line of the source,
+	// otherwise only its first line can be located.
+	multiline bool
+}
+
+// Lookup returns the position of the field with the given path, falling back
+// to the closest parent that exists within the document when the field is
+// not present e.g. when it is required but missing.
+func (p Positions) Lookup(path string) Position {
+	for path != "" {
+		if pos, ok := p.fields[path]; ok {
+			return pos
+		}
+		path = parent(path)
+	}
+
+	return p.document
+}
+
+// LookupLine returns the position of a 1-based line and column within the
+// value of the field with the given path, e.g. of an error within a script,
+// falling back to the position of the field when the line cannot be located.
+func (p Positions) LookupLine(path string, line int, column int) Position {
+	v, ok := p.values[path]
+	if !ok || line < 1 || (line > 1 && !v.multiline) {
+		return p.Lookup(path)
+	}
+
+	pos := v.start
+	pos.Line += line - 1
+	if column > 0 && pos.Column > 0 {
+		pos.Column += column - 1
+	}
+	return pos
+}
+
+func parent(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i < 0 {
+		return ""
+	}
+
+	return path[:i]
+}
+
+// newPositions indexes the position of every field within the document, any
+// failure to parse the document results in all fields being reported against
+// the start of the document.
+func newPositions(file string, doc document) Positions {
+	p := Positions{
+		document: Position{File: file, Document: doc.Index, Line: doc.Line, Column: 1},
+		fields:   map[string]Position{},
+		values:   map[string]scalar{},
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(doc.Data, &root); err != nil || len(root.Content) == 0 {
+		return p
+	}
+
+	p.index("", root.Content[0], strings.Split(string(doc.Data), "\n"))
+
+	return p
+}
+
+func (p Positions) index(path string, node *yaml.Node, lines []string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			child := key.Value
+			if path != "" {
+				child = path + "." + key.Value
+			}
+
+			p.fields[child] = p.position(key)
+			p.indexScalar(child, value, key.Column-1, lines)
+			p.index(child, value, lines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := fmt.Sprintf("%s[%d]", path, i)
+
+			p.fields[child] = p.position(item)
+			p.indexScalar(child, item, node.Column-1, lines)
+			p.index(child, item, lines)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			p.index(path, node.Alias, lines)
+		}
+	}
+}
+
+// indexScalar records where a scalar value starts when its lines can be located
+// within the source, literal blocks whose lines are those of the source, and
+// values on a single line. indent is the indentation of the parent of the
+// value, which an explicit indentation indicator of a block is relative to.
+func (p Positions) indexScalar(path string, node *yaml.Node, indent int, lines []string) {
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+
+	v := scalar{start: p.position(node)}
+	switch {
+	case node.Style&yaml.LiteralStyle != 0:
+		// the value starts on the line after the indicator.
+		v.start.Line++
+		v.start.Column = 0
+		v.multiline = true
+		if n := blockIndent(node, indent, lines); n >= 0 {
+			v.start.Column = n + 1
+		}
+	case strings.Contains(node.Value, "\n"):
+		return
+	case node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0:
+		v.start.Column++
+	}
+
+	p.values[path] = v
+}
+
+// blockIndent returns the indentation of the content of a block scalar, from
+// its explicit indentation indicator, e.g. |2, or otherwise the first line of
+// the block that is not blank. It is -1 when the block has no content.
+func blockIndent(node *yaml.Node, indent int, lines []string) int {
+	if node.Line-1 < len(lines) && node.Column-1 < len(lines[node.Line-1]) {
+		header := lines[node.Line-1][node.Column:]
+		for _, c := range header {
+			if c >= '1' && c <= '9' {
+				return indent + int(c-'0')
+			}
+			if c != '+' && c != '-' {
+				break
+			}
+		}
+	}
+
+	if node.Line >= len(lines) {
+		return -1
+	}
+	for _, line := range lines[node.Line:] {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > indent {
+			return n
+		}
+		break
+	}
+	return -1
+}
+
+func (p Positions) position(node *yaml.Node) Position {
+	return Position{
+		File:     p.document.File,
+		Document: p.document.Document,
+		Line:     p.document.Line + node.Line - 1,
+		Column:   node.Column,
+	}
+}
+
+// fieldPath converts the struct namespace reported by the validator, e.g.
+// Spec.Params[0].Name, into the path of the field within the document, e.g.
+// spec.params[0].name, using the json tags of the validated struct.
+func fieldPath(s interface{}, structNamespace string) string {
+	t := reflect.TypeOf(s)
+
+	var path strings.Builder
+	for _, segment := range splitNamespace(structNamespace) {
+		name, index := segment, ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(name); ok {
+				name = jsonName(field)
+				t = field.Type
+			}
+		}
+
+		for i := strings.Count(index, "["); i > 0; i-- {
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+
+		if path.Len() > 0 {
+			path.WriteString(".")
+		}
+		path.WriteString(name)
+		path.WriteString(index)
+	}
+
+	return path.String()
+}
+
+// splitNamespace splits a namespace on '.', ignoring any within an index as
+// map keys may contain them.
+func splitNamespace(namespace string) []string {
+	var segments []string
+
+	depth, start := 0, 0
+	for i, c := range namespace {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, namespace[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(segments, namespace[start:])
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	tektonGroup      = "tekton.dev"
+	supplyChainGroup = "supply-chain.apps.tanzu.vmware.com"
+
+	latestTektonVersion = "v1"
+)
+
+// ResourceValidator validates a single resource. Diagnostics only need to
+// specify the Field they relate to, the Validator populates the position and
+// details of the resource from the source of the document.
+type ResourceValidator interface {
+	Validate(ctx context.Context, u *unstructured.Unstructured) []Diagnostic
+}
+
+// ResourceValidatorFunc allows a function to be used as a ResourceValidator.
+type ResourceValidatorFunc func(ctx context.Context, u *unstructured.Unstructured) []Diagnostic
+
+// Validate calls f(ctx, u).
+func (f ResourceValidatorFunc) Validate(ctx context.Context, u *unstructured.Unstructured) []Diagnostic {
+	return f(ctx, u)
+}
+
+// Registry holds the ResourceValidator for each GroupVersionKind, it is safe
+// for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	validators map[schema.GroupVersionKind]ResourceValidator
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{validators: map[schema.GroupVersionKind]ResourceValidator{}}
+}
+
+// DefaultRegistry creates a Registry containing the built-in validators for
+// Tekton Tasks and Pipelines and supply chain Components.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(schema.GroupVersionKind{Group: tektonGroup, Kind: "Task"}, ResourceValidatorFunc(ValidateTask))
+	r.Register(schema.GroupVersionKind{Group: tektonGroup, Kind: "Pipeline"}, ResourceValidatorFunc(ValidatePipeline))
+	r.Register(schema.GroupVersionKind{Group: supplyChainGroup, Kind: "Component"}, ResourceValidatorFunc(ValidateComponent))
+	return r
+}
+
+// Register adds the validator for gvk, replacing any existing validator. A
+// gvk with an empty Version matches every version of the group and kind.
+func (r *Registry) Register(gvk schema.GroupVersionKind, v ResourceValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.validators[gvk] = v
+}
+
+// Lookup returns the validator for gvk, preferring one registered for its
+// exact version over one registered for every version.
+func (r *Registry) Lookup(gvk schema.GroupVersionKind) (ResourceValidator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if v, ok := r.validators[gvk]; ok {
+		return v, true
+	}
+
+	v, ok := r.validators[schema.GroupVersionKind{Group: gvk.Group, Kind: gvk.Kind}]
+	return v, ok
+}
@@ -0,0 +1,88 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	r := validator.DefaultRegistry()
+
+	_, ok := r.Lookup(schema.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: "Task"})
+	assert.True(t, ok)
+
+	_, ok = r.Lookup(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Task"})
+	assert.False(t, ok)
+}
+
+func TestRegistryCustomValidator(t *testing.T) {
+	doc := `---
+apiVersion: example.com/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  owner: nobody
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: My_Pipeline
+`
+
+	r := validator.DefaultRegistry()
+	r.Register(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Task"},
+		validator.ResourceValidatorFunc(func(_ context.Context, u *unstructured.Unstructured) []validator.Diagnostic {
+			owner, _, _ := unstructured.NestedString(u.Object, "spec", "owner")
+			if owner != "nobody" {
+				return nil
+			}
+			return []validator.Diagnostic{{Field: "spec.owner", Rule: "EXAMPLE-001", Message: "an owner is required"}}
+		}))
+	r.Register(schema.GroupVersionKind{Group: "tekton.dev", Kind: "Pipeline"},
+		validator.ResourceValidatorFunc(func(context.Context, *unstructured.Unstructured) []validator.Diagnostic {
+			return nil
+		}))
+
+	report, err := validator.New(validator.Options{Registry: r}).ValidateBytes(context.Background(), "custom.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	assert.Equal(t, []validator.Diagnostic{
+		{
+			APIVersion: "example.com/v1",
+			Kind:       "Task",
+			Name:       "my-task",
+			Field:      "spec.owner",
+			Rule:       "EXAMPLE-001",
+			Severity:   validator.SeverityError,
+			Message:    "an owner is required",
+			Position:   validator.Position{File: "custom.yaml", Document: 1, Line: 7, Column: 3},
+		},
+	}, report.Diagnostics)
+	assert.Len(t, report.Resources, 2)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestValidateSkipsOtherGroups(t *testing.T) {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Task",
+		"metadata": map[string]interface{}{
+			"name": "Not_Tekton",
+		},
+	}}
+
+	report, err := validator.New(validator.Options{}).ValidateObject(context.Background(), u)
+	require.NoError(t, err)
+
+	assert.Empty(t, report.Diagnostics)
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, "Task", report.Skipped[0].Kind)
+}
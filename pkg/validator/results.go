@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	ruleResultType       = "CV-TASK-019"
+	ruleResultUndeclared = "CV-TASK-020"
+	ruleResultUnwritten  = "CV-TASK-021"
+	ruleResultSize       = "CV-TASK-022"
+
+	// resultSizeLimit is the size in bytes Tekton limits the results of a
+	// step to, as they are returned within its termination message.
+	resultSizeLimit = 4096
+)
+
+var (
+	// resultReferences matches a reference to the path of a result, e.g.
+	// $(results.name.path) or $(results["name"].path).
+	resultReferences = regexp.MustCompile(`\$\(results(?:\.([\w-]+)|\["([^"]+)"\]|\['([^']+)'\])\.path\)`)
+	// commandSeparator separates the commands of a line of a script.
+	commandSeparator = regexp.MustCompile(`\|\|?|&&|;`)
+)
+
+// resultReference is a single reference to the path of a result.
+type resultReference struct {
+	// text is the reference as written, e.g. $(results.name.path).
+	text string
+	name string
+}
+
+func findResultReferences(s string) []resultReference {
+	var references []resultReference
+	for _, m := range resultReferences.FindAllStringSubmatch(s, -1) {
+		r := resultReference{text: m[0]}
+		for _, name := range m[1:] {
+			if name != "" {
+				r.name = name
+			}
+		}
+		references = append(references, r)
+	}
+	return references
+}
+
+// validateResults checks each result referenced by the stepTemplate, steps and
+// sidecars is declared, that every declared result is written, and that
+// results are not written with the output of commands that could exceed the
+// size limit of Tekton.
+func validateResults(ctx context.Context, u unstructured.Unstructured) []Diagnostic {
+	c := configFrom(ctx)
+
+	var diagnostics []Diagnostic
+	report := func(rule string, severity Severity, field string, message string) {
+		d := newDiagnostic(u, rule, severity, message)
+		d.Field = field
+		diagnostics = append(diagnostics, d)
+	}
+
+	declared := map[string]bool{}
+	results, _, _ := unstructured.NestedSlice(u.Object, "spec", "results")
+	for _, r := range results {
+		result, _ := r.(map[string]interface{})
+		if name, _ := result["name"].(string); name != "" {
+			declared[name] = true
+		}
+	}
+
+	commands := c.Param(ruleResultSize, "commands")
+	written := map[string]bool{}
+	forEachContainerString(u, func(field string, owner string, value string, _ bool) {
+		for _, r := range findResultReferences(value) {
+			written[r.name] = true
+			if !declared[r.name] {
+				report(ruleResultUndeclared, SeverityError, field, fmt.Sprintf("%s%s references the undeclared result %s", owner, r.text, r.name))
+			}
+		}
+
+		if !strings.HasSuffix(field, ".script") {
+			return
+		}
+		for _, line := range strings.Split(value, "\n") {
+			for _, r := range findResultReferences(line) {
+				if command := writerOf(line, r.text); command != "" && contains(commands, command) {
+					report(ruleResultSize, SeverityWarning, field, fmt.Sprintf("%sresult %s is written with the output of %s, which may exceed the %d byte limit of results", owner, r.name, command, resultSizeLimit))
+				}
+			}
+		}
+	})
+
+	for i, r := range results {
+		result, _ := r.(map[string]interface{})
+		name, _ := result["name"].(string)
+		// a result with a value is set from the results of a step.
+		if _, ok := result["value"]; name == "" || ok || written[name] {
+			continue
+		}
+		report(ruleResultUnwritten, SeverityWarning, fmt.Sprintf("spec.results[%d].name", i), fmt.Sprintf("result %s is declared but never written, no step references $(results.%s.path)", name, name))
+	}
+
+	return diagnostics
+}
+
+// writerOf returns the command whose output the line of a script writes to the
+// reference, either by redirecting it or piping it to tee, or empty when the
+// line does not write to the reference.
+func writerOf(line string, reference string) string {
+	before, _, _ := strings.Cut(line, reference)
+	before = strings.TrimSpace(before)
+
+	commands := commandSeparator.Split(before, -1)
+	words := strings.Fields(commands[len(commands)-1])
+	switch {
+	case strings.HasSuffix(before, ">"):
+	case len(words) > 0 && words[0] == "tee" && len(commands) > 1:
+		words = strings.Fields(commands[len(commands)-2])
+	default:
+		return ""
+	}
+
+	for _, w := range words {
+		// skip the variables assigned for the command, e.g. LANG=C.
+		if !strings.Contains(w, "=") {
+			return path.Base(w)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package validator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateResults(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  results:
+  - name: digest
+  - name: files
+    type: array
+  - name: image
+    type: object
+  - name: url
+    type: number
+  - name: unwritten
+  - name: from-step
+    value: $(steps.build.results.digest)
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@` + digest + `
+    args: ["--digest-file", "$(results.digest.path)"]
+    script: |
+      set -e
+      LANG=C /usr/bin/find . -name '*.go' | tee $(results.files.path)
+      echo -n '{}' > $(results["image"].path)
+      echo -n https://example.com >> $(results.url.path)
+      curl -s https://example.com | jq -r .url > $(results.url.path)
+      date > $(results.dates.path)
+`
+
+	report, err := validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, strings.TrimPrefix(d.String(), "carvel.yaml:"))
+	}
+
+	assert.Equal(t, []string{
+		"10:5: [CV-TASK-019] Task/my-task Key 'Spec.Results[2].Properties': is required when Type is object",
+		"13:5: [CV-TASK-019] Task/my-task Key 'Spec.Results[3].Type': number must be one of [string array object]",
+		"31:5: [CV-TASK-020] Task/my-task step build: $(results.dates.path) references the undeclared result dates",
+		"31:5: [CV-TASK-022] Task/my-task step build: result files is written with the output of find, which may exceed the 4096 byte limit of results",
+		"14:5: [CV-TASK-021] Task/my-task result unwritten is declared but never written, no step references $(results.unwritten.path)",
+	}, diagnostics)
+}
+
+func TestValidateResultsCommands(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  results:
+  - name: release
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: build
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: curl -s https://example.com > $(results.release.path)
+`
+
+	config := &validator.Config{Rules: map[string]validator.RuleConfig{
+		"CV-TASK-022": {Params: map[string]validator.Values{"commands": {"cat"}}},
+	}}
+
+	report, err := validator.New(validator.Options{Config: config}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+	assert.Empty(t, report.Diagnostics)
+}
@@ -0,0 +1,467 @@
+package validator
+
+import (
+	"regexp"
+)
+
+const (
+	// RuleDecode is reported when a document is not valid YAML.
+	RuleDecode = "CV-YAML-001"
+	// RuleStructure is reported when a resource does not have the expected structure.
+	RuleStructure = "CV-YAML-002"
+	// RuleSuppressionReason is reported when a resource ignores rules without
+	// giving a reason.
+	RuleSuppressionReason = "CV-SUPPRESS-001"
+	// RuleUnusedSuppression is reported when a resource ignores a rule that is
+	// not reported for it.
+	RuleUnusedSuppression = "CV-SUPPRESS-002"
+)
+
+var index = regexp.MustCompile(`\[[^\]]*\]`)
+
+// Rule describes a single check made against a resource.
+type Rule struct {
+	ID          string
+	Kind        string
+	Severity    Severity
+	Description string
+	// Rationale explains why the rule is checked.
+	Rationale string
+	// Help describes how to fix a failure of the rule.
+	Help string
+	// Params configure the behaviour of the rule, see Config.
+	Params []Param
+	// Optional rules are only checked when enabled by a Config.
+	Optional bool
+
+	// fields are the struct namespaces, without indexes, the rule reports against.
+	fields []string
+	// tags restricts the rule to the given validation tags, all when empty.
+	tags []string
+}
+
+// Param is a parameter of a rule that may be set by a Config.
+type Param struct {
+	Name        string
+	Description string
+	// Multiple reports whether the parameter accepts a list of values.
+	Multiple bool
+	Default  []string
+}
+
+func (p Param) expects() string {
+	if p.Multiple {
+		return "one or more values"
+	}
+	return "a single value"
+}
+
+// Rules are all the checks made against resources.
+var Rules = []Rule{
+	{
+		ID:          RuleDecode,
+		Severity:    SeverityError,
+		Description: "Documents must be valid YAML or JSON with a kind",
+		Rationale:   "A document that cannot be parsed cannot be installed by kapp, and none of its resources can be validated.",
+		Help:        "Fix the syntax of the document so it can be parsed, every document must also specify a kind.",
+	},
+	{
+		ID:          RuleStructure,
+		Severity:    SeverityError,
+		Description: "Resources must match the structure expected for their kind",
+		Rationale:   "A field with the wrong type is rejected by the API server when the package is installed.",
+		Help:        "Ensure each field has the type expected by the resource, e.g. runAsUser must be an integer.",
+	},
+	{
+		ID:          RuleSuppressionReason,
+		Severity:    SeverityError,
+		Description: "Resources that ignore rules must give a reason",
+		Rationale:   "Ignoring a rule hides a problem from every later reviewer, the reason records why it is acceptable.",
+		Help:        "Add the component-validator.dev/ignore-reason annotation explaining why the rules are ignored.",
+	},
+	{
+		ID:          RuleUnusedSuppression,
+		Severity:    SeverityWarning,
+		Description: "Resources should only ignore rules that are reported for them",
+		Rationale:   "A suppression that matches nothing would silently hide the rule if the problem was reintroduced.",
+		Help:        "Remove the rule from the component-validator.dev/ignore annotation, it no longer reports anything for the resource.",
+	},
+	{
+		ID:          RuleStaleBaseline,
+		Severity:    SeverityWarning,
+		Description: "Baseline entries should match a reported diagnostic",
+		Rationale:   "A baseline entry that no longer matches a violation would hide the violation if it was reintroduced.",
+		Help:        "Remove the entry from the baseline, or regenerate the baseline with --write-baseline, as the diagnostic has been fixed.",
+	},
+	{
+		ID:          "CV-TASK-001",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Tasks must use a supported version of the tekton.dev API",
+		Rationale:   "Older versions of the Tekton API are deprecated and may be removed from the clusters the package is installed on.",
+		Help:        "Set apiVersion to tekton.dev/v1, older Tekton APIs are not supported unless allowed by minVersion.",
+		Params:      []Param{minTektonVersion},
+		fields:      []string{"APIVersion", "Kind"},
+	},
+	{
+		ID:          "CV-TASK-002",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Task names must be in kebab-case",
+		Rationale:   "Kubernetes resource names must be valid DNS subdomains, kebab-case names are also consistent across every package.",
+		Help:        "Rename the Task so its name only contains lower case letters, numbers and '-'.",
+		fields:      []string{"Metadata", "Metadata.Name"},
+	},
+	{
+		ID:          "CV-TASK-003",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Tasks must specify a securityContext in their stepTemplate",
+		Rationale:   "Without a securityContext every step runs with the defaults of the container runtime, which are rejected by clusters enforcing the restricted Pod Security Standard.",
+		Help:        "Add spec.stepTemplate.securityContext so every step runs with a restricted security context.",
+		fields:      []string{"Spec", "Spec.StepTemplate", "Spec.StepTemplate.SecurityContext"},
+	},
+	{
+		ID:          "CV-TASK-004",
+		Kind:        "Task",
+		Severity:    SeverityWarning,
+		Description: "Steps should not run as root",
+		Rationale:   "A step running as root can modify the image it runs in and has more access to the node should it escape the container.",
+		Help:        "Set runAsUser to a non-zero user in the securityContext of the stepTemplate, and of any step or sidecar that overrides it, or confirm the Task must run as root.",
+		fields:      []string{"Spec.StepTemplate.SecurityContext.RunAsUser"},
+	},
+	{
+		ID:          "CV-TASK-005",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "runAsNonRoot must be true when, and only when, runAsUser is not root",
+		Rationale:   "runAsNonRoot causes the kubelet to refuse to start a step running as root, it must agree with runAsUser for the step to start.",
+		Help:        "Set runAsNonRoot to true when runAsUser is not 0, and to false when it is.",
+		fields:      []string{"Spec.StepTemplate.SecurityContext.RunAsNonRoot"},
+	},
+	{
+		ID:          "CV-TASK-006",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Steps that do not run as root must drop ALL capabilities",
+		Rationale:   "Capabilities grant privileged operations to a process, the restricted Pod Security Standard requires every capability to be dropped.",
+		Help:        "Set capabilities.drop to [ALL] in the securityContext of the stepTemplate, and of any step or sidecar that overrides it.",
+		fields:      []string{"Spec.StepTemplate.SecurityContext.Capabilities", "Spec.StepTemplate.SecurityContext.Capabilities.Drop"},
+	},
+	{
+		ID:          "CV-TASK-007",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Steps must not allow privilege escalation",
+		Rationale:   "Privilege escalation allows a process to gain more privileges than its parent, e.g. through setuid binaries.",
+		Help:        "Set allowPrivilegeEscalation to false in the securityContext of the stepTemplate, and of any step or sidecar that overrides it.",
+		fields:      []string{"Spec.StepTemplate.SecurityContext.AllowPrivilegeEscalation"},
+	},
+	{
+		ID:          "CV-TASK-008",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Steps must use an allowed seccomp profile",
+		Rationale:   "A seccomp profile restricts the system calls a step can make, the restricted Pod Security Standard requires RuntimeDefault or Localhost.",
+		Help:        "Set seccompProfile.type to RuntimeDefault, or one of the configured types, in the securityContext of the stepTemplate and of any step or sidecar that overrides it.",
+		Params: []Param{
+			{Name: "types", Description: "The allowed seccomp profile types", Multiple: true, Default: []string{"RuntimeDefault"}},
+		},
+		fields: []string{"Spec.StepTemplate.SecurityContext.SeccompProfile", "Spec.StepTemplate.SecurityContext.SeccompProfile.Type"},
+	},
+	{
+		ID:          "CV-TASK-009",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Task param names must be in kebab-case",
+		Rationale:   "Params are referenced by name from Pipelines and Components, kebab-case names are consistent across every package.",
+		Help:        "Rename the param so its name only contains lower case letters, numbers and '-', updating any $(params.name) references.",
+		fields:      []string{"Spec.Params.Name"},
+	},
+	{
+		ID:          "CV-TASK-010",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Task result names must be in kebab-case",
+		Rationale:   "Results are referenced by name from Pipelines and Components, kebab-case names are consistent across every package.",
+		Help:        "Rename the result so its name only contains lower case letters, numbers and '-', updating any $(results.name.path) references.",
+		fields:      []string{"Spec.Results.Name"},
+	},
+	{
+		ID:          "CV-TASK-011",
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Steps must not run privileged",
+		Rationale:   "A privileged container has every capability and access to the devices of the node, it is not isolated from the node.",
+		Help:        "Remove privileged, or set it to false, in the securityContext of the stepTemplate and of any step or sidecar that overrides it.",
+		fields:      []string{"Spec.StepTemplate.SecurityContext.Privileged"},
+	},
+	{
+		ID:          ruleImageDigest,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Step images must be pinned by a sha256 digest",
+		Rationale:   "A tag can be moved to another image at any time, only a digest ensures the imgpkg bundle of the package is reproducible.",
+		Help:        "Append the digest of the image to its reference, e.g. image: cgr.dev/chainguard/bash@sha256:<digest>.",
+	},
+	{
+		ID:          ruleImageLatest,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Step images must not use the latest tag",
+		Rationale:   "The latest tag changes with every release of the image, an image without a tag or digest also uses it.",
+		Help:        "Reference a specific version of the image, pinned by its digest.",
+	},
+	{
+		ID:          ruleImageRegistry,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Step images must come from an allowed registry",
+		Rationale:   "Images are relocated with the package, pulling them from an unapproved registry bypasses the scanning of the approved registries.",
+		Help:        "Use an image from one of the configured registries, or add its registry to the registries parameter.",
+		Params: []Param{
+			{Name: "registries", Description: "The allowed registries, or repositories within a registry e.g. ghcr.io/org, every registry is allowed when empty", Multiple: true},
+		},
+	},
+	{
+		ID:          ruleImageUnresolved,
+		Kind:        "Task",
+		Severity:    SeverityInfo,
+		Description: "Step images that reference a variable without a default cannot be checked",
+		Rationale:   "An image set by a param is only known when the Task is run, the image rules are checked against the default of the param instead.",
+		Help:        "Give the param a default pinned by a digest, and check the images passed to the param where the Task is used.",
+	},
+	{
+		ID:          ruleParamUndeclared,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Steps must only reference declared params",
+		Rationale:   "Tekton rejects a Task that references a param it does not declare, a typo in a reference is only found when the Task is applied.",
+		Help:        "Declare the param in spec.params, or correct the name of the reference.",
+	},
+	{
+		ID:          ruleParamUnused,
+		Kind:        "Task",
+		Severity:    SeverityWarning,
+		Description: "Params should be referenced by the Task",
+		Rationale:   "A param that is never referenced has no effect, users setting it will expect it to change how the Task runs.",
+		Help:        "Reference the param, or remove it from spec.params.",
+	},
+	{
+		ID:          ruleParamExpansion,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Params must be referenced as their type allows",
+		Rationale:   "Array and object params can only be expanded with [*] as an entire item of args or command, elsewhere Tekton rejects the Task or substitutes nothing.",
+		Help:        "Expand array and object params with $(params.name[*]) as an item of args or command, reference a single item with $(params.name[0]) or $(params.name.key), and only expand array and object params.",
+	},
+	{
+		ID:          ruleResultType,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Task results must have a type of string, array or object",
+		Rationale:   "Tekton rejects results of any other type, and cannot check the value written to an object result without its properties.",
+		Help:        "Set the type of the result to string, array or object, and declare the properties of object results.",
+		fields:      []string{"Spec.Results.Type", "Spec.Results.Properties"},
+	},
+	{
+		ID:          ruleResultUndeclared,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Steps must only write declared results",
+		Rationale:   "Tekton rejects a Task that references a result it does not declare, a typo in a reference is only found when the Task is applied.",
+		Help:        "Declare the result in spec.results, or correct the name of the $(results.name.path) reference.",
+	},
+	{
+		ID:          ruleResultUnwritten,
+		Kind:        "Task",
+		Severity:    SeverityWarning,
+		Description: "Task results should be written by a step",
+		Rationale:   "A TaskRun fails when a declared result is not written, unless the result is set from the results of a step.",
+		Help:        "Write the result to $(results.name.path) in a step, or remove it from spec.results.",
+	},
+	{
+		ID:          ruleResultSize,
+		Kind:        "Task",
+		Severity:    SeverityWarning,
+		Description: "Task results should not be written with output that may exceed the result size limit",
+		Rationale:   "Results are returned within the termination message of a step, which Tekton limits to 4096 bytes for all of its results, a TaskRun fails when the output of a command such as curl exceeds it.",
+		Help:        "Write large output to a workspace and return its path or digest as the result, or filter the output, e.g. with jq, before writing it.",
+		Params: []Param{
+			{Name: "commands", Description: "The commands whose output may exceed the limit when written to a result", Multiple: true, Default: []string{"curl", "wget", "find", "ls", "tar", "base64", "kubectl", "env", "printenv"}},
+		},
+	},
+	{
+		ID:          ruleScriptSyntax,
+		Kind:        "Task",
+		Severity:    SeverityError,
+		Description: "Step scripts must be valid shell",
+		Rationale:   "A syntax error in a script is only found when a step runs it, scripts for sh, bash and ksh are parsed from their shebang, or as sh without one.",
+		Help:        "Correct the script at the reported line, e.g. close any unterminated quotes, if blocks or loops.",
+	},
+	{
+		ID:          ruleScriptStrict,
+		Kind:        "Task",
+		Severity:    SeverityWarning,
+		Description: "Step scripts should set -euo pipefail",
+		Rationale:   "By default a script continues after a command fails or an unset variable is referenced, and the step succeeds when its last command does.",
+		Help:        "Start the script with set -euo pipefail, or set -eu in POSIX shells without pipefail.",
+		Optional:    true,
+	},
+	{
+		ID:          ruleScriptUnquoted,
+		Kind:        "Task",
+		Severity:    SeverityWarning,
+		Description: "Step scripts should quote param references",
+		Rationale:   "Tekton substitutes params into the script before it runs, an unquoted value containing whitespace or glob characters is split into several words or expanded.",
+		Help:        "Quote the reference, e.g. \"$(params.name)\", or assign it to a variable first.",
+		Optional:    true,
+	},
+	{
+		ID:          "CV-PIPELINE-001",
+		Kind:        "Pipeline",
+		Severity:    SeverityError,
+		Description: "Pipelines must use a supported version of the tekton.dev API",
+		Rationale:   "Older versions of the Tekton API are deprecated and may be removed from the clusters the package is installed on.",
+		Help:        "Set apiVersion to tekton.dev/v1, older Tekton APIs are not supported unless allowed by minVersion.",
+		Params:      []Param{minTektonVersion},
+		fields:      []string{"APIVersion", "Kind"},
+	},
+	{
+		ID:          "CV-PIPELINE-002",
+		Kind:        "Pipeline",
+		Severity:    SeverityError,
+		Description: "Pipeline names must be in kebab-case",
+		Rationale:   "Kubernetes resource names must be valid DNS subdomains, kebab-case names are also consistent across every package.",
+		Help:        "Rename the Pipeline so its name only contains lower case letters, numbers and '-'.",
+		fields:      []string{"Metadata", "Metadata.Name"},
+	},
+	{
+		ID:          "CV-COMPONENT-001",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Components must use a supported version of the supply-chain.apps.tanzu.vmware.com API",
+		Rationale:   "Components are only reconciled by the version of the supply chain API installed on the cluster.",
+		Help:        "Set apiVersion to supply-chain.apps.tanzu.vmware.com/v1alpha1, or one of the configured apiVersions.",
+		Params: []Param{
+			{Name: "apiVersions", Description: "The allowed apiVersions", Multiple: true, Default: []string{"supply-chain.apps.tanzu.vmware.com/v1alpha1"}},
+		},
+		fields: []string{"APIVersion", "Kind"},
+	},
+	{
+		ID:          "CV-COMPONENT-002",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Component names must be in kebab-case",
+		Rationale:   "Kubernetes resource names must be valid DNS subdomains, kebab-case names are also consistent across every package.",
+		Help:        "Rename the Component so its name only contains lower case letters, numbers and '-'.",
+		fields:      []string{"Metadata", "Metadata.Name"},
+		tags:        []string{"required", "kebab-case"},
+	},
+	{
+		ID:          "CV-COMPONENT-003",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Component names must end in a semantic version",
+		Rationale:   "Components are immutable once published, the version in the name allows a new version to be installed alongside the old.",
+		Help:        "Suffix the Component name with its version, e.g. my-component-1.0.0.",
+		fields:      []string{"Metadata.Name"},
+		tags:        []string{"contains-semver"},
+	},
+	{
+		ID:          "CV-COMPONENT-004",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Component names must not contain '-component'",
+		Rationale:   "Components are listed by name, repeating the kind in the name adds nothing.",
+		Help:        "Remove '-component' from the name, it is implied by the kind.",
+		fields:      []string{"Metadata.Name"},
+		tags:        []string{"not-contains-component"},
+	},
+	{
+		ID:          "CV-COMPONENT-005",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Components must be labelled with the catalog they belong to",
+		Rationale:   "The catalog label determines where the Component is listed, unlabelled Components cannot be found by users.",
+		Help:        "Add the label supply-chain.apps.tanzu.vmware.com/catalog: tanzu, or the configured label and catalog.",
+		Params: []Param{
+			{Name: "label", Description: "The label naming the catalog", Default: []string{"supply-chain.apps.tanzu.vmware.com/catalog"}},
+			{Name: "values", Description: "The allowed catalogs", Multiple: true, Default: []string{"tanzu"}},
+		},
+		fields: []string{"Metadata.Labels"},
+	},
+	{
+		ID:          "CV-COMPONENT-006",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Components must have a description",
+		Rationale:   "The description is shown to users choosing the Components to build a supply chain from.",
+		Help:        "Add spec.description describing what the Component does.",
+		fields:      []string{"Spec", "Spec.Description"},
+	},
+	{
+		ID:          "CV-COMPONENT-007",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Components must reference a pipeline by a kebab-case name",
+		Rationale:   "A Component runs a Pipeline for each workload, without a valid pipelineRef nothing is run.",
+		Help:        "Set spec.pipelineRun.pipelineRef.name to the kebab-case name of the Pipeline to run.",
+		fields:      []string{"Spec.PipelineRun", "Spec.PipelineRun.PipelineRef", "Spec.PipelineRun.PipelineRef.Name"},
+	},
+	{
+		ID:          "CV-COMPONENT-008",
+		Kind:        "Component",
+		Severity:    SeverityError,
+		Description: "Component pipelineRun param names must be in kebab-case",
+		Rationale:   "Params are passed by name to the Pipeline, kebab-case names are consistent across every package.",
+		Help:        "Rename the param so its name only contains lower case letters, numbers and '-'.",
+		fields:      []string{"Spec.PipelineRun.Params.Name"},
+	},
+}
+
+var minTektonVersion = Param{
+	Name:        "minVersion",
+	Description: "The oldest version of the tekton.dev API allowed",
+	Default:     []string{latestTektonVersion},
+}
+
+// LookupRule returns the rule with the given ID.
+func LookupRule(id string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (r Rule) param(name string) (Param, bool) {
+	for _, p := range r.Params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Param{}, false
+}
+
+// ruleFor returns the rule a failed validation of kind reports against,
+// falling back to the validation tag with a severity of error.
+func ruleFor(kind string, structNamespace string, tag string) Rule {
+	namespace := index.ReplaceAllString(structNamespace, "")
+	for _, r := range Rules {
+		if r.Kind == kind && contains(r.fields, namespace) && (len(r.tags) == 0 || contains(r.tags, tag)) {
+			return r
+		}
+	}
+	return Rule{ID: tag, Kind: kind, Severity: SeverityError}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
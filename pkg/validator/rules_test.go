@@ -0,0 +1,69 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	ids := map[string]bool{}
+	for _, r := range validator.Rules {
+		assert.False(t, ids[r.ID], "duplicate rule %s", r.ID)
+		ids[r.ID] = true
+
+		assert.NotEmpty(t, r.Description, r.ID)
+		_, err := validator.ParseSeverity(string(r.Severity))
+		assert.NoError(t, err, r.ID)
+
+		found, ok := validator.LookupRule(r.ID)
+		assert.True(t, ok, r.ID)
+		assert.Equal(t, r.ID, found.ID)
+	}
+}
+
+func TestRuleExamples(t *testing.T) {
+	rules := func(r validator.Rule, source string) []string {
+		// images are only checked against the registries when configured
+		config := &validator.Config{Rules: map[string]validator.RuleConfig{
+			"CV-TASK-014": {Params: map[string]validator.Values{"registries": {"cgr.dev", "gcr.io/kaniko-project"}}},
+		}}
+		// optional rules are only checked by their own examples
+		if r.Optional {
+			enabled := true
+			config.Rules[r.ID] = validator.RuleConfig{Enabled: &enabled}
+		}
+
+		report, err := validator.New(validator.Options{Config: config}).ValidateBytes(context.Background(), "example.yaml", []byte(source))
+		require.NoError(t, err)
+
+		var ids []string
+		for _, d := range report.Diagnostics {
+			ids = append(ids, d.Rule)
+		}
+		return ids
+	}
+
+	for _, r := range validator.Rules {
+		e, ok := r.Example()
+		if !ok {
+			continue
+		}
+
+		t.Run(r.ID, func(t *testing.T) {
+			assert.Contains(t, rules(r, e.Failing), r.ID)
+			assert.Empty(t, rules(r, e.Passing))
+		})
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	assert.True(t, validator.SeverityError.AtLeast(validator.SeverityWarning))
+	assert.True(t, validator.SeverityWarning.AtLeast(validator.SeverityWarning))
+	assert.False(t, validator.SeverityInfo.AtLeast(validator.SeverityWarning))
+	assert.False(t, validator.SeverityWarning.AtLeast(validator.SeverityError))
+}
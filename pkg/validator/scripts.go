@@ -0,0 +1,188 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+const (
+	ruleScriptSyntax   = "CV-TASK-023"
+	ruleScriptStrict   = "CV-TASK-024"
+	ruleScriptUnquoted = "CV-TASK-025"
+
+	// defaultScriptShell runs scripts without a shebang, as in Tekton.
+	defaultScriptShell = "sh"
+)
+
+// shells are the interpreters whose scripts are parsed, keyed by their name
+// within a shebang, scripts for any other interpreter are not checked.
+var shells = map[string]syntax.LangVariant{
+	"sh":   syntax.LangPOSIX,
+	"ash":  syntax.LangPOSIX,
+	"dash": syntax.LangPOSIX,
+	"bash": syntax.LangBash,
+	"ksh":  syntax.LangMirBSDKorn,
+	"mksh": syntax.LangMirBSDKorn,
+}
+
+// shebang matches the interpreter of a script and its arguments, e.g.
+// #!/usr/bin/env bash or #!/bin/sh -e.
+var shebang = regexp.MustCompile(`^#!\s*(\S+)(.*)`)
+
+// interpreterOf returns the name of the interpreter of the script and the
+// arguments it is given, scripts without a shebang are run by sh.
+func interpreterOf(script string) (string, []string) {
+	m := shebang.FindStringSubmatch(strings.SplitN(script, "\n", 2)[0])
+	if m == nil {
+		return defaultScriptShell, nil
+	}
+
+	name, args := path.Base(m[1]), strings.Fields(m[2])
+	if name == "env" {
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			return "", nil
+		}
+		name, args = path.Base(args[0]), args[1:]
+	}
+	return name, args
+}
+
+// validateScripts parses the script of the stepTemplate and of each step and
+// sidecar, reporting syntax errors at their line within the script. The
+// optional rules check that scripts exit on errors, and that param references
+// are quoted.
+func validateScripts(u unstructured.Unstructured) []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(rule string, severity Severity, field string, line uint, column uint, message string) {
+		d := newDiagnostic(u, rule, severity, message)
+		d.Field = field
+		d.line, d.column = int(line), int(column)
+		diagnostics = append(diagnostics, d)
+	}
+
+	forEachContainer(u, func(p string, owner string, container map[string]interface{}) {
+		script, _ := container["script"].(string)
+		field := p + "script"
+
+		name, args := interpreterOf(script)
+		lang, ok := shells[name]
+		if script == "" || !ok {
+			return
+		}
+
+		f, err := syntax.NewParser(syntax.Variant(lang)).Parse(strings.NewReader(script), "")
+		if err != nil {
+			var parseErr syntax.ParseError
+			var langErr syntax.LangError
+			switch {
+			case errors.As(err, &parseErr):
+				report(ruleScriptSyntax, SeverityError, field, parseErr.Pos.Line(), parseErr.Pos.Col(), fmt.Sprintf("%sline %d of the %s script: %s", owner, parseErr.Pos.Line(), name, parseErr.Text))
+			case errors.As(err, &langErr):
+				report(ruleScriptSyntax, SeverityError, field, langErr.Pos.Line(), langErr.Pos.Col(), fmt.Sprintf("%sline %d of the %s script: %s", owner, langErr.Pos.Line(), name, strings.TrimPrefix(langErr.Error(), langErr.Pos.String()+": ")))
+			default:
+				report(ruleScriptSyntax, SeverityError, field, 0, 0, fmt.Sprintf("%s%s script: %s", owner, name, err))
+			}
+			return
+		}
+
+		if missing := missingShellOptions(f, lang, args); len(missing) > 0 {
+			report(ruleScriptStrict, SeverityWarning, field, 1, 0, fmt.Sprintf("%s%s script does not set %s, so it continues after a command fails", owner, name, strings.Join(missing, " ")))
+		}
+
+		for _, cs := range unquotedParams(f, script) {
+			report(ruleScriptUnquoted, SeverityWarning, field, cs.Pos().Line(), cs.Pos().Col(), fmt.Sprintf("%sline %d of the %s script: %s is not quoted, its value is split on whitespace and expanded as a glob", owner, cs.Pos().Line(), name, script[cs.Pos().Offset():cs.End().Offset()]))
+		}
+	})
+
+	return diagnostics
+}
+
+// missingShellOptions returns the options of set -euo pipefail that are not set
+// by the shebang or a set command at the top level of the script. pipefail is
+// not required of POSIX shells as not all of them support it.
+func missingShellOptions(f *syntax.File, lang syntax.LangVariant, args []string) []string {
+	set := map[string]bool{}
+	apply := func(args []string) {
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			switch {
+			case arg == "-o" && i+1 < len(args):
+				i++
+				set[args[i]] = true
+			case strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--"):
+				for _, o := range arg[1:] {
+					switch o {
+					case 'e':
+						set["errexit"] = true
+					case 'u':
+						set["nounset"] = true
+					case 'o':
+						if i+1 < len(args) {
+							i++
+							set[args[i]] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	apply(args)
+	for _, stmt := range f.Stmts {
+		call, ok := stmt.Cmd.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 || call.Args[0].Lit() != "set" {
+			continue
+		}
+
+		var words []string
+		for _, w := range call.Args[1:] {
+			words = append(words, w.Lit())
+		}
+		apply(words)
+	}
+
+	var missing []string
+	if !set["errexit"] {
+		missing = append(missing, "-e")
+	}
+	if !set["nounset"] {
+		missing = append(missing, "-u")
+	}
+	if !set["pipefail"] && lang != syntax.LangPOSIX {
+		missing = append(missing, "-o pipefail")
+	}
+	return missing
+}
+
+// unquotedParams returns the param references within the script that are not
+// within double quotes. As Tekton substitutes params before the script is run
+// a reference is parsed as a command substitution. References within
+// assignments and heredocs are not split so are not returned.
+func unquotedParams(f *syntax.File, script string) []*syntax.CmdSubst {
+	var unquoted []*syntax.CmdSubst
+	syntax.Walk(f, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.DblQuoted, *syntax.Assign:
+			return false
+		case *syntax.Redirect:
+			return n.Hdoc == nil
+		case *syntax.CmdSubst:
+			text := script[n.Pos().Offset():n.End().Offset()]
+			if paramReferences.FindString(text) == text {
+				unquoted = append(unquoted, n)
+				return false
+			}
+		}
+		return true
+	})
+	return unquoted
+}
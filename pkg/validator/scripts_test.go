@@ -0,0 +1,151 @@
+package validator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const scriptTask = `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  params:
+  - name: message
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: unterminated
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: |
+      #!/bin/bash
+      echo "$(params.message)"
+      if [ -n "$HOME" ]; then
+        echo 'home
+      fi
+  - name: single
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: 'echo "$(params.message)'
+  - name: python
+    image: cgr.dev/chainguard/python@` + digest + `
+    script: |
+      #!/usr/bin/env python3
+      print("$(params.message)"
+  - name: posix
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: |
+      set -eu
+      messages=("$(params.message)")
+  - name: strict
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: |
+      #!/usr/bin/env -S bash -e
+      set -o nounset -o pipefail
+      MESSAGE=$(params.message)
+      cat <<EOF
+      $(params.message)
+      EOF
+      echo "$MESSAGE" $(params.message)
+  - name: lax
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: |
+      #!/bin/bash
+      set -e
+      echo $(params.message)
+`
+
+func TestValidateScripts(t *testing.T) {
+	report, err := validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", []byte(scriptTask))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, strings.TrimPrefix(d.String(), "carvel.yaml:"))
+	}
+
+	assert.Equal(t, []string{
+		"25:14: [CV-TASK-023] Task/my-task step unterminated: line 4 of the bash script: reached EOF without closing quote '",
+		"29:19: [CV-TASK-023] Task/my-task step single: line 1 of the sh script: reached EOF without closing quote \"",
+		"39:16: [CV-TASK-023] Task/my-task step posix: line 2 of the sh script: arrays are a bash/mksh feature",
+	}, diagnostics)
+}
+
+func TestValidateScriptsOptional(t *testing.T) {
+	enabled := true
+	config := &validator.Config{Rules: map[string]validator.RuleConfig{
+		"CV-TASK-024": {Enabled: &enabled},
+		"CV-TASK-025": {Enabled: &enabled},
+	}}
+
+	report, err := validator.New(validator.Options{Config: config}).ValidateBytes(context.Background(), "carvel.yaml", []byte(scriptTask))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		if d.Rule != "CV-TASK-023" {
+			diagnostics = append(diagnostics, strings.TrimPrefix(d.String(), "carvel.yaml:"))
+		}
+	}
+
+	assert.Equal(t, []string{
+		"49:23: [CV-TASK-025] Task/my-task step strict: line 7 of the bash script: $(params.message) is not quoted, its value is split on whitespace and expanded as a glob",
+		"53:7: [CV-TASK-024] Task/my-task step lax: bash script does not set -u -o pipefail, so it continues after a command fails",
+		"55:12: [CV-TASK-025] Task/my-task step lax: line 3 of the bash script: $(params.message) is not quoted, its value is split on whitespace and expanded as a glob",
+	}, diagnostics)
+}
+
+func TestValidateScriptsPosition(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: blank
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: |
+
+      echo 'unterminated
+  - name: indicator
+    image: cgr.dev/chainguard/bash@` + digest + `
+    script: |2-
+         echo "unterminated
+`
+
+	report, err := validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, strings.TrimPrefix(d.String(), "carvel.yaml:"))
+	}
+
+	assert.Equal(t, []string{
+		"21:12: [CV-TASK-023] Task/my-task step blank: line 2 of the sh script: reached EOF without closing quote '",
+		"25:15: [CV-TASK-023] Task/my-task step indicator: line 1 of the sh script: reached EOF without closing quote \"",
+	}, diagnostics)
+}
@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Source is a named input containing one or more documents.
+type Source struct {
+	// File is the name diagnostics within the source are reported against.
+	File string
+	Data []byte
+}
+
+type job struct {
+	file string
+	doc  document
+}
+
+// ValidateSources decodes and validates every document of each source using
+// a pool of Options.Jobs workers. The report is ordered by file and document
+// regardless of the order in which documents are validated. An error is
+// returned when a custom rule of Options.Config fails to compile.
+func (v *Validator) ValidateSources(ctx context.Context, sources ...Source) (*Report, error) {
+	if _, err := v.options.Config.custom(); err != nil {
+		return nil, err
+	}
+
+	var jobs []job
+	for _, s := range sources {
+		for _, doc := range splitDocuments(s.Data) {
+			jobs = append(jobs, job{file: s.File, doc: doc})
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].file != jobs[j].file {
+			return jobs[i].file < jobs[j].file
+		}
+		return jobs[i].doc.Index < jobs[j].doc.Index
+	})
+
+	if v.options.Policy != nil {
+		var resources []unstructured.Unstructured
+		for _, j := range jobs {
+			if u, err := decode(j.file, j.doc); err == nil {
+				resources = append(resources, u)
+			}
+		}
+
+		var err error
+		if ctx, err = v.preparePolicy(ctx, resources); err != nil {
+			return nil, err
+		}
+	}
+
+	workers := v.options.Jobs
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// once a document fails fast, only the documents after it are cancelled,
+	// those before it are still validated in full as the report ends with the
+	// first document that fails.
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		failed  = len(jobs)
+		cancels = map[int]context.CancelFunc{}
+		next    = make(chan int)
+		reports = make([]*Report, len(jobs))
+	)
+
+	start := func(i int) (context.Context, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if i > failed {
+			return nil, false
+		}
+		jobCtx, cancel := context.WithCancel(ctx)
+		cancels[i] = cancel
+		return jobCtx, true
+	}
+
+	finish := func(i int, r *Report) {
+		mu.Lock()
+		defer mu.Unlock()
+		cancels[i]()
+		delete(cancels, i)
+		if !v.failedFast(r) || i > failed {
+			return
+		}
+		failed = i
+		for j, cancel := range cancels {
+			if j > failed {
+				cancel()
+			}
+		}
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				jobCtx, ok := start(i)
+				if !ok {
+					continue
+				}
+				reports[i] = v.validateDocument(jobCtx, jobs[i].file, jobs[i].doc)
+				finish(i, reports[i])
+			}
+		}()
+	}
+
+	for i := range jobs {
+		mu.Lock()
+		stop := i > failed
+		mu.Unlock()
+		if stop || ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, r := range reports {
+		if r == nil {
+			continue
+		}
+		report.Merge(r)
+		if v.failedFast(r) {
+			break
+		}
+	}
+
+	return report, nil
+}
+
+func (v *Validator) validateDocument(ctx context.Context, file string, doc document) *Report {
+	u, err := decode(file, doc)
+	if err != nil {
+		report := &Report{}
+		if d, ok := v.configure(v.decodeDiagnostic(err)); ok {
+			report.Diagnostics = append(report.Diagnostics, d)
+		}
+		v.baseline(report)
+		return report
+	}
+
+	return v.validate(ctx, u, newPositions(file, doc))
+}
+
+// failedFast reports whether validation should stop after r.
+func (v *Validator) failedFast(r *Report) bool {
+	return v.options.FailFast != "" && r.Failed(v.options.FailFast)
+}
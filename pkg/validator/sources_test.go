@@ -0,0 +1,106 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pipelines(names ...string) []byte {
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "---\napiVersion: tekton.dev/v1\nkind: Pipeline\nmetadata:\n  name: %s\n", name)
+	}
+	return []byte(b.String())
+}
+
+func TestValidateSourcesOrdering(t *testing.T) {
+	sources := []validator.Source{
+		{File: "b.yaml", Data: pipelines("B_One", "B_Two", "B_Three")},
+		{File: "a.yaml", Data: pipelines("A_One", "A_Two")},
+	}
+
+	for _, jobs := range []int{1, 4, 16} {
+		report, err := validator.New(validator.Options{Jobs: jobs}).ValidateSources(context.Background(), sources...)
+		require.NoError(t, err)
+
+		var got []string
+		for _, d := range report.Diagnostics {
+			got = append(got, fmt.Sprintf("%s:%d %s", d.Position.File, d.Position.Document, d.Name))
+		}
+
+		assert.Equal(t, []string{
+			"a.yaml:1 A_One",
+			"a.yaml:2 A_Two",
+			"b.yaml:1 B_One",
+			"b.yaml:2 B_Two",
+			"b.yaml:3 B_Three",
+		}, got, "jobs=%d", jobs)
+		assert.Len(t, report.Resources, 5)
+	}
+}
+
+func TestValidateSourcesFailFast(t *testing.T) {
+	source := validator.Source{File: "carvel.yaml", Data: pipelines("one", "Two", "three", "Four")}
+
+	report, err := validator.New(validator.Options{Jobs: 1, FailFast: validator.SeverityError}).ValidateSources(context.Background(), source)
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, "Two", report.Diagnostics[0].Name)
+	assert.Len(t, report.Resources, 2)
+}
+
+func TestValidateSourcesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := validator.New(validator.Options{Jobs: 4}).ValidateSources(ctx, validator.Source{Data: pipelines("one", "two")})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestValidateSourcesFailFastPolicy(t *testing.T) {
+	p, err := validator.LoadPolicy(writePolicy(t, `package main
+
+deny[msg] {
+  startswith(input.metadata.name, "bad")
+  msg := sprintf("%s is bad", [input.metadata.name])
+}
+
+# never matches, but is slow enough to still be evaluating when cancelled
+deny_slow[msg] {
+  count([x | x := numbers.range(1, 2000)[_]; x < 0]) > 0
+  msg := "unreachable"
+}
+`))
+	require.NoError(t, err)
+
+	var names []string
+	for i := 0; i < 64; i++ {
+		name := fmt.Sprintf("p-%d", i)
+		if i == 32 || i > 48 {
+			name = fmt.Sprintf("bad-%d", i)
+		}
+		names = append(names, name)
+	}
+	source := validator.Source{File: "many.yaml", Data: pipelines(names...)}
+
+	v := validator.New(validator.Options{Jobs: 16, FailFast: validator.SeverityError, Policy: p})
+	for i := 0; i < 5; i++ {
+		report, err := v.ValidateSources(context.Background(), source)
+		require.NoError(t, err)
+
+		var got []string
+		for _, d := range report.Diagnostics {
+			got = append(got, fmt.Sprintf("%s %s", d.Name, d.Message))
+		}
+		require.Equal(t, []string{"bad-32 bad-32 is bad"}, got)
+		assert.Len(t, report.Resources, 33)
+	}
+}
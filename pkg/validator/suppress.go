@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// AnnotationIgnore lists the IDs of the rules not reported for a resource,
+	// separated by commas.
+	AnnotationIgnore = "component-validator.dev/ignore"
+	// AnnotationIgnoreReason explains why the rules of AnnotationIgnore are not
+	// reported, it is required.
+	AnnotationIgnoreReason = "component-validator.dev/ignore-reason"
+
+	annotationsField = "metadata.annotations"
+)
+
+// Suppression is a diagnostic that was not reported because its rule is
+// ignored by the resource.
+type Suppression struct {
+	Diagnostic
+	Reason string `json:"reason"`
+}
+
+// suppress moves the diagnostics of rules ignored by the resource from the
+// report to its suppressions, reporting any ignored rule that matched nothing.
+func (v *Validator) suppress(report *Report, u unstructured.Unstructured, positions Positions) {
+	annotations := u.GetAnnotations()
+
+	value, ok := annotations[AnnotationIgnore]
+	if !ok {
+		return
+	}
+
+	reason := strings.TrimSpace(annotations[AnnotationIgnoreReason])
+	if reason == "" {
+		d := newDiagnostic(u, RuleSuppressionReason, SeverityError,
+			fmt.Sprintf("Key '%s': %s must explain why the rules of %s are ignored", annotationsField, AnnotationIgnoreReason, AnnotationIgnore))
+		d.Field = annotationsField
+		v.report(report, u, positions, d)
+		return
+	}
+
+	used := map[string]bool{}
+	var ignored []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ignored = append(ignored, id)
+		}
+	}
+
+	var diagnostics []Diagnostic
+	for _, d := range report.Diagnostics {
+		if contains(ignored, d.Rule) {
+			used[d.Rule] = true
+			report.Suppressed = append(report.Suppressed, Suppression{Diagnostic: d, Reason: reason})
+			continue
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	report.Diagnostics = diagnostics
+
+	for _, id := range ignored {
+		if used[id] || !v.options.Config.Enabled(id) {
+			continue
+		}
+
+		d := newDiagnostic(u, RuleUnusedSuppression, SeverityWarning,
+			fmt.Sprintf("Key '%s': %s ignores %s but it is not reported", annotationsField, AnnotationIgnore, id))
+		d.Field = annotationsField
+		v.report(report, u, positions, d)
+	}
+}
@@ -0,0 +1,75 @@
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rootTask = `---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+  annotations:
+%s
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: false
+      runAsUser: 0
+      seccompProfile:
+        type: RuntimeDefault
+`
+
+func validateAnnotations(t *testing.T, annotations string) *validator.Report {
+	t.Helper()
+
+	doc := []byte(fmt.Sprintf(rootTask, annotations))
+	report, err := validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", doc)
+	require.NoError(t, err)
+	return report
+}
+
+func TestSuppress(t *testing.T) {
+	report := validateAnnotations(t, `    component-validator.dev/ignore: "CV-TASK-004"
+    component-validator.dev/ignore-reason: "the build requires root"`)
+
+	assert.Empty(t, report.Diagnostics)
+	require.Len(t, report.Suppressed, 1)
+	assert.Equal(t, "CV-TASK-004", report.Suppressed[0].Rule)
+	assert.Equal(t, "the build requires root", report.Suppressed[0].Reason)
+	assert.Equal(t, 17, report.Suppressed[0].Position.Line)
+}
+
+func TestSuppressUnused(t *testing.T) {
+	report := validateAnnotations(t, `    component-validator.dev/ignore: "CV-TASK-004, CV-TASK-006"
+    component-validator.dev/ignore-reason: "the build requires root"`)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, validator.RuleUnusedSuppression, report.Diagnostics[0].Rule)
+	assert.Equal(t, validator.SeverityWarning, report.Diagnostics[0].Severity)
+	assert.Equal(t, "metadata.annotations", report.Diagnostics[0].Field)
+	assert.Contains(t, report.Diagnostics[0].Message, "CV-TASK-006")
+	assert.Len(t, report.Suppressed, 1)
+}
+
+func TestSuppressWithoutReason(t *testing.T) {
+	report := validateAnnotations(t, `    component-validator.dev/ignore: "CV-TASK-004"`)
+
+	var rules []string
+	for _, d := range report.Diagnostics {
+		rules = append(rules, d.Rule)
+	}
+	assert.Equal(t, []string{"CV-TASK-004", validator.RuleSuppressionReason}, rules)
+	assert.Empty(t, report.Suppressed)
+}
@@ -0,0 +1,179 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stoewer/go-strcase"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ValidateTask validates a Tekton Task, it is registered for every version of
+// tekton.dev/Task by DefaultRegistry.
+func ValidateTask(ctx context.Context, u *unstructured.Unstructured) []Diagnostic {
+	validate, translator, err := getValidator()
+	if err != nil {
+		return []Diagnostic{newDiagnostic(*u, RuleStructure, SeverityError, err.Error())}
+	}
+
+	fields := &struct {
+		APIVersion string `json:"apiVersion" validate:"required,tekton-api-version=CV-TASK-001"`
+		Kind       string `json:"kind" validate:"required,eq=Task"`
+		Metadata   struct {
+			Name string `json:"name" validate:"required,kebab-case"`
+		} `json:"metadata" validate:"required"`
+		Spec struct {
+			Params []struct {
+				Name  string `json:"name" validate:"required,kebab-case"`
+				Value string `json:"value"`
+			} `json:"params" validate:"dive"`
+			Results []struct {
+				Name       string                 `json:"name" validate:"required,kebab-case"`
+				Type       string                 `json:"type" validate:"omitempty,oneof=string array object"`
+				Properties map[string]interface{} `json:"properties" validate:"required_if=Type object"`
+			} `json:"results" validate:"dive"`
+			StepTemplate struct {
+				SecurityContext securityContextFields `json:"securityContext" validate:"required"`
+			} `json:"stepTemplate" validate:"required"`
+		} `json:"spec" validate:"required"`
+	}{}
+
+	if diagnostics := convert(*u, &fields); diagnostics != nil {
+		return diagnostics
+	}
+
+	diagnostics := translate(ctx, *u, fields, validate.StructCtx(ctx, fields), translator)
+	diagnostics = append(diagnostics, validateContainers(ctx, *u, diagnostics)...)
+	diagnostics = append(diagnostics, validateImages(ctx, *u)...)
+	diagnostics = append(diagnostics, validateParams(*u)...)
+	diagnostics = append(diagnostics, validateResults(ctx, *u)...)
+	return append(diagnostics, validateScripts(*u)...)
+}
+
+// securityContextFields are the fields of the securityContext of the
+// stepTemplate, and the effective securityContext of each step and sidecar.
+type securityContextFields struct {
+	AllowPrivilegeEscalation bool `json:"allowPrivilegeEscalation" validate:"eq=false"`
+	Capabilities             struct {
+		Drop []string `json:"drop" validate:"contains-all"`
+	} `json:"capabilities" validate:"required"`
+	Privileged     bool `json:"privileged" validate:"eq=false"`
+	RunAsNonRoot   bool `json:"runAsNonRoot" validate:"compatible-nonroot"`
+	RunAsUser      int  `json:"runAsUser" validate:"non-root-user"`
+	SeccompProfile struct {
+		Type string `json:"type" validate:"required,seccomp-profile=CV-TASK-008"`
+	} `json:"seccompProfile" validate:"required"`
+}
+
+const stepTemplatePath = "spec.stepTemplate."
+
+// validateContainers validates the effective securityContext of each step and
+// sidecar with the rules of the stepTemplate. As in Tekton the securityContext
+// of a step is merged over that of the stepTemplate, while sidecars only use
+// their own. Steps that do not override the stepTemplate are not validated, nor
+// are the violations a step inherits that are reported for the stepTemplate.
+func validateContainers(ctx context.Context, u unstructured.Unstructured, reported []Diagnostic) []Diagnostic {
+	template, _, _ := unstructured.NestedMap(u.Object, "spec", "stepTemplate", "securityContext")
+
+	inherited := map[string]bool{}
+	for _, d := range reported {
+		inherited[d.Rule+" "+strings.TrimPrefix(d.Field, stepTemplatePath)] = true
+	}
+
+	var diagnostics []Diagnostic
+	for _, kind := range []struct {
+		field    string
+		name     string
+		template bool
+	}{
+		{field: "steps", name: "step", template: true},
+		{field: "sidecars", name: "sidecar"},
+	} {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", kind.field)
+		for i, c := range containers {
+			container, _ := c.(map[string]interface{})
+			own, overrides := container["securityContext"].(map[string]interface{})
+
+			effective := own
+			if kind.template {
+				if !overrides {
+					continue
+				}
+				effective = mergeSecurityContext(template, own)
+			}
+
+			path := fmt.Sprintf("spec.%s[%d].", kind.field, i)
+			namespace := fmt.Sprintf("Spec.%s[%d].", strcase.UpperCamelCase(kind.field), i)
+			prefix := fmt.Sprintf("%s %d: ", kind.name, i)
+			if name, _ := container["name"].(string); name != "" {
+				prefix = fmt.Sprintf("%s %s: ", kind.name, name)
+			}
+
+			for _, d := range validateSecurityContext(ctx, u, effective) {
+				relative := strings.TrimPrefix(d.Field, stepTemplatePath)
+				if kind.template && inherited[d.Rule+" "+relative] {
+					continue
+				}
+
+				d.Field = path + relative
+				d.Message = prefix + strings.ReplaceAll(d.Message, "Spec.StepTemplate.", namespace)
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// validateSecurityContext validates a securityContext as if it were the
+// securityContext of the stepTemplate.
+func validateSecurityContext(ctx context.Context, u unstructured.Unstructured, securityContext map[string]interface{}) []Diagnostic {
+	validate, translator, err := getValidator()
+	if err != nil {
+		return []Diagnostic{newDiagnostic(u, RuleStructure, SeverityError, err.Error())}
+	}
+
+	fields := &struct {
+		Spec struct {
+			StepTemplate struct {
+				SecurityContext securityContextFields `json:"securityContext"`
+			} `json:"stepTemplate"`
+		} `json:"spec"`
+	}{}
+
+	object := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"stepTemplate": map[string]interface{}{"securityContext": securityContext},
+		},
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, fields); err != nil {
+		d := newDiagnostic(u, RuleStructure, SeverityError, err.Error())
+		d.Field = stepTemplatePath + "securityContext"
+		return []Diagnostic{d}
+	}
+
+	return translate(ctx, u, fields, validate.StructCtx(ctx, fields), translator)
+}
+
+// mergeSecurityContext merges the securityContext of a step over that of the
+// stepTemplate, fields of the step replace those of the stepTemplate while
+// nested objects are merged.
+func mergeSecurityContext(template map[string]interface{}, step map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range template {
+		merged[k] = v
+	}
+
+	for k, v := range step {
+		t, ok := merged[k].(map[string]interface{})
+		if s, isMap := v.(map[string]interface{}); ok && isMap {
+			merged[k] = mergeSecurityContext(t, s)
+			continue
+		}
+		merged[k] = v
+	}
+
+	return merged
+}
@@ -0,0 +1,327 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	playground "github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/stoewer/go-strcase"
+	"go.uber.org/multierr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// convert populates fields from the object, reporting a diagnostic against the
+// document if the object does not have the expected structure.
+func convert(u unstructured.Unstructured, fields interface{}) []Diagnostic {
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, fields)
+	if err != nil {
+		return []Diagnostic{newDiagnostic(u, RuleStructure, SeverityError, err.Error())}
+	}
+
+	return nil
+}
+
+func translate(ctx context.Context, u unstructured.Unstructured, fields interface{}, err error, translator ut.Translator) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	var diagnostics []Diagnostic
+	for _, e := range err.(playground.ValidationErrors) {
+		rule := ruleFor(u.GetKind(), e.StructNamespace(), e.Tag())
+
+		msg := e.Translate(translator)
+		if params, ok := configuredTranslations[e.Tag()]; ok {
+			msg, _ = translator.T(e.Tag(), params(configFrom(ctx), e)...)
+		}
+
+		d := newDiagnostic(u, rule.ID, rule.Severity, msg)
+		d.Field = fieldPath(fields, e.StructNamespace())
+
+		diagnostics = append(diagnostics, d)
+	}
+
+	return diagnostics
+}
+
+// configuredTranslations are the translations of validations whose parameter
+// names the rule they check, they return the values the message is rendered
+// with from the parameters of the rule.
+var configuredTranslations = map[string]func(c *Config, fe playground.FieldError) []string{
+	"tekton-api-version": func(c *Config, fe playground.FieldError) []string {
+		minVersion := c.Param(fe.Param(), "minVersion")[0]
+		expected := tektonGroup + "/" + minVersion
+		if minVersion != latestTektonVersion {
+			expected += " or newer"
+		}
+		return []string{fe.StructNamespace(), fe.Value().(string), expected}
+	},
+	"api-version": func(c *Config, fe playground.FieldError) []string {
+		return []string{fe.StructNamespace(), fe.Value().(string), strings.Join(c.Param(fe.Param(), "apiVersions"), " or ")}
+	},
+	"seccomp-profile": func(c *Config, fe playground.FieldError) []string {
+		return []string{fe.StructNamespace(), fe.Value().(string), strings.Join(c.Param(fe.Param(), "types"), " or ")}
+	},
+	"contains-catalog-label": func(c *Config, fe playground.FieldError) []string {
+		return []string{fe.StructNamespace(), c.Param(fe.Param(), "label")[0], strings.Join(c.Param(fe.Param(), "values"), " or ")}
+	},
+}
+
+// semanticVersion matches a name ending in a semantic version, see
+// https://semver.org/#is-there-a-suggested-regular-expression-regex-to-check-a-semver-string
+var semanticVersion = regexp.MustCompile(`(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// shared holds the validator and translator used for every document, both are
+// safe for concurrent use once built and cache the details of each struct.
+var shared struct {
+	once       sync.Once
+	validate   *playground.Validate
+	translator ut.Translator
+	err        error
+}
+
+func getValidator() (*playground.Validate, ut.Translator, error) {
+	shared.once.Do(func() {
+		shared.validate, shared.translator, shared.err = newValidator()
+	})
+	return shared.validate, shared.translator, shared.err
+}
+
+func newValidator() (*playground.Validate, ut.Translator, error) {
+	translator := en.New()
+	uni := ut.New(translator, translator)
+
+	trans, _ := uni.GetTranslator("en")
+	validate := playground.New(playground.WithRequiredStructEnabled())
+
+	err := entranslations.RegisterDefaultTranslations(validate, trans)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = multierr.Combine(
+		validate.RegisterValidation("kebab-case", ValidateKebabCase),
+		validate.RegisterValidation("contains-semver", ValidateContainsSemanticVersion),
+		validate.RegisterValidationCtx("contains-catalog-label", ValidateContainsCatalogLabel),
+		validate.RegisterValidationCtx("tekton-api-version", ValidateTektonAPIVersion),
+		validate.RegisterValidationCtx("api-version", ValidateAPIVersion),
+		validate.RegisterValidationCtx("seccomp-profile", ValidateSeccompProfile),
+		validate.RegisterValidation("contains-all", ValidateContainsAll),
+		validate.RegisterValidation("not-contains-component", ValidateNotContainsComponent),
+		validate.RegisterValidation("compatible-nonroot", ValidateNonRoot),
+		validate.RegisterValidation("non-root-user", ValidateNonRootUser),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`failed to add custom validations": %s`, err)
+	}
+
+	err = validate.RegisterTranslation("required", trans, func(ut ut.Translator) error {
+		return ut.Add("required", "Key '{0}': is required", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("required", fe.StructNamespace())
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("eq", trans, func(ut ut.Translator) error {
+		return ut.Add("eq", "Key '{0}': Expected {1} to equal {2}", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("eq", fe.StructNamespace(), fmt.Sprintf("%v", fe.Value()), fe.Param())
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("required_if", trans, func(ut ut.Translator) error {
+		return ut.Add("required_if", "Key '{0}': is required when {1}", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("required_if", fe.StructNamespace(), strings.Replace(fe.Param(), " ", " is ", 1))
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("oneof", trans, func(ut ut.Translator) error {
+		return ut.Add("oneof", "Key '{0}': {1} must be one of [{2}]", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("oneof", fe.StructNamespace(), fmt.Sprintf("%v", fe.Value()), fe.Param())
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("kebab-case", trans, func(ut ut.Translator) error {
+		return ut.Add("kebab-case", "Key '{0}': {1} does not appear to be in kebab-case", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("kebab-case", fe.StructNamespace(), fe.Value().(string))
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("contains-semver", trans, func(ut ut.Translator) error {
+		return ut.Add("contains-semver", "Key '{0}': {1} Does not end in a semantic version", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("contains-semver", fe.StructNamespace(), fe.Value().(string))
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = multierr.Combine(
+		trans.Add("tekton-api-version", "Key '{0}': Expected {1} to equal {2}", true),
+		trans.Add("api-version", "Key '{0}': Expected {1} to equal {2}", true),
+		trans.Add("seccomp-profile", "Key '{0}': Expected {1} to equal {2}", true),
+		trans.Add("contains-catalog-label", "Key '{0}': Does not contain the key/value '{1}: {2}'", true),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("contains-all", trans, func(ut ut.Translator) error {
+		return ut.Add("contains-all", "Key '{0}': Must only contain the values [ALL]", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("contains-all", fe.StructNamespace())
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("non-root-user", trans, func(ut ut.Translator) error {
+		return ut.Add("non-root-user", "Key '{0}': Runs as root, please ensure this was intended", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("non-root-user", fe.StructNamespace())
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate.RegisterTranslation("not-contains-component", trans, func(ut ut.Translator) error {
+		return ut.Add("not-contains-component", "Key '{0}': Must not contain 'component'", true)
+	}, func(ut ut.Translator, fe playground.FieldError) string {
+		t, _ := ut.T("not-contains-component", fe.StructNamespace())
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return validate, trans, nil
+}
+
+func ValidateKebabCase(fl playground.FieldLevel) bool {
+	name := fl.Field().String()
+	return name == strcase.KebabCase(name)
+}
+
+func ValidateContainsSemanticVersion(fl playground.FieldLevel) bool {
+	name := fl.Field().String()
+	return semanticVersion.MatchString(name)
+}
+
+// ValidateContainsCatalogLabel checks the labels contain one of the catalogs
+// allowed by the rule named by the tag parameter.
+func ValidateContainsCatalogLabel(ctx context.Context, fl playground.FieldLevel) bool {
+	numberOfEntries := len(fl.Field().MapKeys())
+	if numberOfEntries == 0 {
+		return false
+	}
+
+	c := configFrom(ctx)
+	m := fl.Field().Interface().(map[string]string)
+	v, ok := m[c.Param(fl.Param(), "label")[0]]
+	if !ok {
+		return false
+	}
+
+	return contains(c.Param(fl.Param(), "values"), v)
+}
+
+// ValidateTektonAPIVersion checks the apiVersion is in the tekton.dev group
+// and no older than the minVersion of the rule named by the tag parameter.
+func ValidateTektonAPIVersion(ctx context.Context, fl playground.FieldLevel) bool {
+	gv, err := schema.ParseGroupVersion(fl.Field().String())
+	if err != nil || gv.Group != tektonGroup {
+		return false
+	}
+
+	minVersion := configFrom(ctx).Param(fl.Param(), "minVersion")[0]
+	return version.CompareKubeAwareVersionStrings(gv.Version, minVersion) >= 0
+}
+
+// ValidateAPIVersion checks the apiVersion is one of the apiVersions of the
+// rule named by the tag parameter.
+func ValidateAPIVersion(ctx context.Context, fl playground.FieldLevel) bool {
+	return contains(configFrom(ctx).Param(fl.Param(), "apiVersions"), fl.Field().String())
+}
+
+// ValidateSeccompProfile checks the seccomp profile type is one of the types
+// of the rule named by the tag parameter.
+func ValidateSeccompProfile(ctx context.Context, fl playground.FieldLevel) bool {
+	return contains(configFrom(ctx).Param(fl.Param(), "types"), fl.Field().String())
+}
+
+func ValidateContainsAll(fl playground.FieldLevel) bool {
+	top := fl.Top()
+
+	sc := reflect.Indirect(top).FieldByName("Spec").FieldByName("StepTemplate").FieldByName("SecurityContext")
+	runAsUser := sc.FieldByName("RunAsUser").Int()
+
+	isValid := true
+	m := fl.Field().Interface().([]string)
+
+	if runAsUser != 0 {
+		isValid = len(m) == 1 && m[0] == "ALL"
+	}
+
+	return isValid
+}
+
+func ValidateNonRootUser(fl playground.FieldLevel) bool {
+	return fl.Field().Int() != 0
+}
+
+func ValidateNotContainsComponent(fl playground.FieldLevel) bool {
+	name := fl.Field().String()
+	return !strings.Contains(name, "-component")
+}
+
+func ValidateNonRoot(fl playground.FieldLevel) bool {
+	top := fl.Top()
+
+	sc := reflect.Indirect(top).FieldByName("Spec").FieldByName("StepTemplate").FieldByName("SecurityContext")
+
+	runAsUser := sc.FieldByName("RunAsUser").Int()
+	nonRoot := fl.Field().Bool()
+
+	if runAsUser == 0 && !nonRoot {
+		return true
+	} else if runAsUser != 0 && nonRoot {
+		return true
+	} else if runAsUser == 0 && nonRoot {
+		return false
+	} else if runAsUser != 0 && !nonRoot {
+		return false
+	}
+
+	return false
+}
@@ -0,0 +1,198 @@
+// Package validator validates Tekton and supply chain resources before their
+// inclusion in a carvel package, it can be embedded by any tool as it does no
+// logging and holds no global state.
+package validator
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Options configures a Validator.
+type Options struct {
+	// StrictParse reports documents that cannot be decoded with a severity of
+	// error, otherwise they are reported as warnings.
+	StrictParse bool
+	// Jobs is the number of documents validated concurrently, GOMAXPROCS is
+	// used when zero.
+	Jobs int
+	// FailFast stops validation once a diagnostic at least this severe is
+	// reported, every document is validated when empty.
+	FailFast Severity
+	// Config enables, disables and configures rules, and defines custom rules,
+	// the defaults of every rule are used when nil.
+	Config *Config
+	// Baseline holds the known diagnostics that are not reported.
+	Baseline *Baseline
+	// Policy holds the Rego policies evaluated against every resource.
+	Policy *Policy
+	// Registry holds the validator for each kind, DefaultRegistry is used when nil.
+	Registry *Registry
+}
+
+// Validator validates Tekton and supply chain resources.
+type Validator struct {
+	options Options
+}
+
+// New creates a Validator configured by options.
+func New(options Options) *Validator {
+	if options.Registry == nil {
+		options.Registry = DefaultRegistry()
+	}
+	return &Validator{options: options}
+}
+
+// ValidateReader decodes each document read from r and validates it.
+func (v *Validator) ValidateReader(ctx context.Context, r io.Reader) (*Report, error) {
+	source, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.ValidateBytes(ctx, "", source)
+}
+
+// ValidateFile decodes each document within the file and validates it.
+func (v *Validator) ValidateFile(ctx context.Context, file string) (*Report, error) {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.ValidateBytes(ctx, file, source)
+}
+
+// ValidateBytes decodes each document within source and validates it,
+// reporting any diagnostic against file.
+func (v *Validator) ValidateBytes(ctx context.Context, file string, source []byte) (*Report, error) {
+	return v.ValidateSources(ctx, Source{File: file, Data: source})
+}
+
+// ValidateObject validates a single resource, as the object has no source the
+// position of any diagnostic is not reported.
+func (v *Validator) ValidateObject(ctx context.Context, u unstructured.Unstructured) (*Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := v.options.Config.custom(); err != nil {
+		return nil, err
+	}
+
+	ctx, err := v.preparePolicy(ctx, []unstructured.Unstructured{u})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.validate(ctx, u, Positions{}), nil
+}
+
+func (v *Validator) validate(ctx context.Context, u unstructured.Unstructured, positions Positions) *Report {
+	report := &Report{}
+	resource := newResource(u, positions.Lookup(""))
+
+	var validators []ResourceValidator
+	if rv, ok := v.options.Registry.Lookup(u.GroupVersionKind()); ok {
+		validators = append(validators, rv)
+	}
+	if p := policyFrom(ctx); p != nil {
+		validators = append(validators, p)
+	}
+
+	// any error compiling the custom rules is returned before validating
+	custom, _ := v.options.Config.custom()
+	for _, r := range custom {
+		if r.Match.matches(&u) {
+			validators = append(validators, r)
+		}
+	}
+
+	if len(validators) == 0 {
+		report.Skipped = append(report.Skipped, resource)
+		return report
+	}
+
+	report.Resources = append(report.Resources, resource)
+	for _, rv := range validators {
+		v.report(report, u, positions, rv.Validate(withConfig(ctx, v.options.Config), &u)...)
+	}
+	v.suppress(report, u, positions)
+	v.baseline(report)
+
+	return report
+}
+
+// preparePolicy returns a context carrying the policy prepared with resources
+// as its inventory, ctx is returned as is when there is no policy.
+func (v *Validator) preparePolicy(ctx context.Context, resources []unstructured.Unstructured) (context.Context, error) {
+	if v.options.Policy == nil {
+		return ctx, nil
+	}
+
+	p, err := v.options.Policy.prepare(ctx, resources)
+	if err != nil {
+		return nil, err
+	}
+	return withPolicy(ctx, p), nil
+}
+
+func (v *Validator) baseline(report *Report) {
+	if v.options.Baseline != nil {
+		v.options.Baseline.Apply(report)
+	}
+}
+
+// report adds each diagnostic of the resource whose rule is enabled.
+func (v *Validator) report(report *Report, u unstructured.Unstructured, positions Positions, diagnostics ...Diagnostic) {
+	for _, d := range diagnostics {
+		if d, ok := v.configure(complete(d, u, positions)); ok {
+			report.Diagnostics = append(report.Diagnostics, d)
+		}
+	}
+}
+
+// configure applies the Config to the diagnostic, reporting false when its
+// rule is disabled.
+func (v *Validator) configure(d Diagnostic) (Diagnostic, bool) {
+	if !v.options.Config.Enabled(d.Rule) {
+		return d, false
+	}
+	d.Severity = v.options.Config.Severity(d.Rule, d.Severity)
+	return d, true
+}
+
+// complete populates any details of the diagnostic left unspecified by a
+// ResourceValidator from the resource and its source.
+func complete(d Diagnostic, u unstructured.Unstructured, positions Positions) Diagnostic {
+	if d.Kind == "" {
+		d.APIVersion = u.GetAPIVersion()
+		d.Kind = u.GetKind()
+		d.Name = u.GetName()
+		d.Namespace = u.GetNamespace()
+	}
+	if d.Severity == "" {
+		d.Severity = SeverityError
+	}
+	if d.Position == (Position{}) {
+		d.Position = positions.LookupLine(d.Field, d.line, d.column)
+	}
+	return d
+}
+
+func (v *Validator) decodeDiagnostic(err *DecodeError) Diagnostic {
+	severity := SeverityWarning
+	if v.options.StrictParse {
+		severity = SeverityError
+	}
+
+	return Diagnostic{
+		Rule:     RuleDecode,
+		Severity: severity,
+		Message:  err.Error(),
+		Position: err.Position,
+	}
+}
@@ -0,0 +1,301 @@
+package validator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/garethjevans/component-validator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidate(t *testing.T) {
+	doc := `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+  namespace: my-namespace
+spec:
+  params:
+  - name: Bad_Param
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - all
+      runAsNonRoot: false
+---
+kind: [broken
+`
+
+	v := validator.New(validator.Options{StrictParse: true})
+
+	report, err := v.ValidateBytes(context.Background(), "config/carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	assert.Equal(t, []validator.Diagnostic{
+		{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+			Name:       "my-task",
+			Namespace:  "my-namespace",
+			Field:      "spec.params[0].name",
+			Rule:       "CV-TASK-009",
+			Severity:   validator.SeverityError,
+			Message:    "Key 'Spec.Params[0].Name': Bad_Param does not appear to be in kebab-case",
+			Position:   validator.Position{File: "config/carvel.yaml", Document: 2, Line: 14, Column: 5},
+		},
+		{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+			Name:       "my-task",
+			Namespace:  "my-namespace",
+			Field:      "spec.stepTemplate.securityContext.runAsUser",
+			Rule:       "CV-TASK-004",
+			Severity:   validator.SeverityWarning,
+			Message:    "Key 'Spec.StepTemplate.SecurityContext.RunAsUser': Runs as root, please ensure this was intended",
+			Position:   validator.Position{File: "config/carvel.yaml", Document: 2, Line: 16, Column: 5},
+		},
+		{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+			Name:       "my-task",
+			Namespace:  "my-namespace",
+			Field:      "spec.stepTemplate.securityContext.seccompProfile",
+			Rule:       "CV-TASK-008",
+			Severity:   validator.SeverityError,
+			Message:    "Key 'Spec.StepTemplate.SecurityContext.SeccompProfile': is required",
+			Position:   validator.Position{File: "config/carvel.yaml", Document: 2, Line: 16, Column: 5},
+		},
+		{
+			APIVersion: "tekton.dev/v1",
+			Kind:       "Task",
+			Name:       "my-task",
+			Namespace:  "my-namespace",
+			Field:      "spec.params[0].name",
+			Rule:       "CV-TASK-017",
+			Severity:   validator.SeverityWarning,
+			Message:    "param Bad_Param is declared but never referenced",
+			Position:   validator.Position{File: "config/carvel.yaml", Document: 2, Line: 14, Column: 5},
+		},
+		{
+			Rule:     validator.RuleDecode,
+			Severity: validator.SeverityError,
+			Message:  "unable to decode document 3 (line 23): yaml: line 23: did not find expected ',' or ']'",
+			Position: validator.Position{File: "config/carvel.yaml", Document: 3, Line: 23},
+		},
+	}, report.Diagnostics)
+}
+
+func TestValidateTaskSteps(t *testing.T) {
+	doc := `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: my-task
+spec:
+  stepTemplate:
+    securityContext:
+      allowPrivilegeEscalation: true
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: RuntimeDefault
+  steps:
+  - name: inherits
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+  - name: overrides
+    image: cgr.dev/chainguard/bash@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    securityContext:
+      privileged: true
+      runAsUser: 0
+  sidecars:
+  - name: registry
+    image: cgr.dev/chainguard/registry@sha256:5a3c464e409d62860a5462fa866aa8293a187c7d6b60826638b5118e2bf331a4
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      runAsNonRoot: true
+      runAsUser: 1001
+      seccompProfile:
+        type: Unconfined
+`
+
+	report, err := validator.New(validator.Options{}).ValidateBytes(context.Background(), "carvel.yaml", []byte(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, d.String())
+	}
+
+	assert.Equal(t, []string{
+		"carvel.yaml:8:7: [CV-TASK-007] Task/my-task Key 'Spec.StepTemplate.SecurityContext.AllowPrivilegeEscalation': Expected true to equal false",
+		"carvel.yaml:22:7: [CV-TASK-011] Task/my-task step overrides: Key 'Spec.Steps[1].SecurityContext.Privileged': Expected true to equal false",
+		"carvel.yaml:21:5: [CV-TASK-005] Task/my-task step overrides: Key: 'Spec.Steps[1].SecurityContext.RunAsNonRoot' Error:Field validation for 'RunAsNonRoot' failed on the 'compatible-nonroot' tag",
+		"carvel.yaml:23:7: [CV-TASK-004] Task/my-task step overrides: Key 'Spec.Steps[1].SecurityContext.RunAsUser': Runs as root, please ensure this was intended",
+		"carvel.yaml:35:9: [CV-TASK-008] Task/my-task sidecar registry: Key 'Spec.Sidecars[0].SecurityContext.SeccompProfile.Type': Expected Unconfined to equal RuntimeDefault",
+	}, diagnostics)
+}
+
+func TestValidateReader(t *testing.T) {
+	doc := `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: my-pipeline
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+---
+kind: [broken
+`
+
+	report, err := validator.New(validator.Options{}).ValidateReader(context.Background(), strings.NewReader(doc))
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, validator.RuleDecode, report.Diagnostics[0].Rule)
+	assert.Equal(t, validator.SeverityWarning, report.Diagnostics[0].Severity)
+
+	require.Len(t, report.Resources, 1)
+	assert.Equal(t, "Pipeline", report.Resources[0].Kind)
+	assert.Equal(t, 2, report.Resources[0].Position.Line)
+
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, "ConfigMap", report.Skipped[0].Kind)
+	assert.Equal(t, 7, report.Skipped[0].Position.Line)
+}
+
+func TestValidateJSONStream(t *testing.T) {
+	doc := `{"apiVersion": "tekton.dev/v1", "kind": "Pipeline", "metadata": {"name": "my-pipeline"}}
+{
+  "apiVersion": "tekton.dev/v1",
+  "kind": "Pipeline",
+  "metadata": {
+    "name": "My_Pipeline"
+  }
+}
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "my-config"}} {"kind": [broken
+`
+
+	report, err := validator.New(validator.Options{}).ValidateReader(context.Background(), strings.NewReader(doc))
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 2)
+	assert.Equal(t, "CV-PIPELINE-002", report.Diagnostics[0].Rule)
+	assert.Equal(t, validator.Position{Document: 2, Line: 6, Column: 5}, report.Diagnostics[0].Position)
+	assert.Equal(t, validator.RuleDecode, report.Diagnostics[1].Rule)
+	assert.Equal(t, 4, report.Diagnostics[1].Position.Document)
+
+	require.Len(t, report.Resources, 2)
+	assert.Equal(t, 1, report.Resources[0].Position.Line)
+	assert.Equal(t, 2, report.Resources[1].Position.Line)
+
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, 9, report.Skipped[0].Position.Line)
+}
+
+func TestValidateJSONThenYAML(t *testing.T) {
+	doc := `{"apiVersion": "tekton.dev/v1", "kind": "Pipeline", "metadata": {"name": "json-pipeline"}}
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: Yaml_Pipeline
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: Last_Pipeline
+`
+
+	report, err := validator.New(validator.Options{}).ValidateReader(context.Background(), strings.NewReader(doc))
+	require.NoError(t, err)
+
+	var diagnostics []string
+	for _, d := range report.Diagnostics {
+		diagnostics = append(diagnostics, d.String())
+	}
+	assert.Equal(t, []string{
+		"6:3: [CV-PIPELINE-002] Pipeline/Yaml_Pipeline Key 'Metadata.Name': Yaml_Pipeline does not appear to be in kebab-case",
+		"16:3: [CV-PIPELINE-002] Pipeline/Last_Pipeline Key 'Metadata.Name': Last_Pipeline does not appear to be in kebab-case",
+	}, diagnostics)
+
+	require.Len(t, report.Resources, 3)
+	assert.Equal(t, validator.Position{Document: 1, Line: 1, Column: 1}, report.Resources[0].Position)
+	assert.Equal(t, validator.Position{Document: 2, Line: 3, Column: 1}, report.Resources[1].Position)
+	assert.Equal(t, validator.Position{Document: 4, Line: 13, Column: 1}, report.Resources[2].Position)
+
+	require.Len(t, report.Skipped, 1)
+	assert.Equal(t, validator.Position{Document: 3, Line: 8, Column: 1}, report.Skipped[0].Position)
+}
+
+func TestValidateFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "carvel.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: My_Pipeline
+`), 0o600))
+
+	report, err := validator.New(validator.Options{}).ValidateFile(context.Background(), file)
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, "CV-PIPELINE-002", report.Diagnostics[0].Rule)
+	assert.Equal(t, validator.Position{File: file, Document: 1, Line: 5, Column: 3}, report.Diagnostics[0].Position)
+
+	_, err = validator.New(validator.Options{}).ValidateFile(context.Background(), filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestValidateObject(t *testing.T) {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "Pipeline",
+		"metadata": map[string]interface{}{
+			"name": "My_Pipeline",
+		},
+	}}
+
+	report, err := validator.New(validator.Options{}).ValidateObject(context.Background(), u)
+	require.NoError(t, err)
+
+	require.Len(t, report.Diagnostics, 1)
+	assert.Equal(t, "CV-PIPELINE-002", report.Diagnostics[0].Rule)
+	assert.Equal(t, "metadata.name", report.Diagnostics[0].Field)
+	assert.Equal(t, validator.Position{}, report.Diagnostics[0].Position)
+}
+
+func TestValidateCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := validator.New(validator.Options{}).ValidateReader(ctx, strings.NewReader("kind: Pipeline"))
+	assert.ErrorIs(t, err, context.Canceled)
+}